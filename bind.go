@@ -0,0 +1,43 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Bind decodes the request body of c, once validated by the middleware,
+// into a T. JSON bodies are decoded from the bytes buffered during
+// validation and the body can still be read afterward. Other media types
+// are bound with echo.DefaultBinder. An empty body returns the zero T.
+func Bind[T any](c echo.Context) (T, error) {
+	var v T
+
+	req := c.Request()
+	b, err := readBody(req)
+	if err != nil {
+		return v, fmt.Errorf("failed reading request body: %v", err)
+	}
+	if len(b) == 0 {
+		return v, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if mediaType != echo.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json") {
+		err = (&echo.DefaultBinder{}).BindBody(c, &v)
+		req.Body = io.NopCloser(bytes.NewReader(b))
+		return v, err
+	}
+
+	err = json.Unmarshal(b, &v)
+	if err != nil {
+		return v, fmt.Errorf("failed decoding request body: %v", err)
+	}
+
+	return v, nil
+}
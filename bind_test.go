@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDocument struct {
+	Title string `json:"title" form:"title"`
+	Name  string `json:"name" form:"name"`
+}
+
+func TestBind(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    testDocument
+	}{
+		{"json", echo.MIMEApplicationJSON, `{"title": "report"}`, testDocument{Title: "report"}},
+		{"form", echo.MIMEApplicationForm, "name=report", testDocument{Name: "report"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/documents", func(c echo.Context) error {
+				doc, err := Bind[testDocument](c)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expected, doc)
+
+				// the body can still be read
+				b, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.body, string(b))
+
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/documents", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+		})
+	}
+}
+
+func TestBind_Errors(t *testing.T) {
+	e := echo.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewBufferString(`{"title": 1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	_, err := Bind[testDocument](c)
+	assert.ErrorContains(t, err, "failed decoding request body")
+
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	c = e.NewContext(req, httptest.NewRecorder())
+
+	doc, err := Bind[testDocument](c)
+	assert.NoError(t, err)
+	assert.Equal(t, testDocument{}, doc)
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FieldNameCase defines how field names are written in validation errors.
+type FieldNameCase int
+
+const (
+	// CaseAsIs keeps field names as written in the spec.
+	CaseAsIs FieldNameCase = iota
+	// CaseCamel writes field names in camelCase.
+	CaseCamel
+	// CaseSnake writes field names in snake_case.
+	CaseSnake
+)
+
+// apply converts each segment of path to the case.
+func (fc FieldNameCase) apply(path []string) []string {
+	if fc == CaseAsIs {
+		return path
+	}
+
+	res := make([]string, len(path))
+	for i, segment := range path {
+		switch fc {
+		case CaseCamel:
+			res[i] = toCamel(segment)
+		case CaseSnake:
+			res[i] = toSnake(segment)
+		default:
+			res[i] = segment
+		}
+	}
+	return res
+}
+
+func toCamel(s string) string {
+	var sb strings.Builder
+	upper := false
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '-':
+			upper = i > 0
+		case upper:
+			sb.WriteRune(unicode.ToUpper(r))
+			upper = false
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func toSnake(s string) string {
+	runes := []rune(s)
+	var sb strings.Builder
+	for i, r := range runes {
+		if r == '-' {
+			sb.WriteRune('_')
+			continue
+		}
+
+		if unicode.IsUpper(r) {
+			// start a new word on a lower to upper transition, or at the
+			// last upper of an acronym followed by a lower, e.g. "userID"
+			// and "IDToken"
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1]))) {
+				sb.WriteRune('_')
+			}
+			sb.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,98 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_FieldNameCase(t *testing.T) {
+	testCases := []struct {
+		name      string
+		fieldCase FieldNameCase
+		errors    []string
+	}{
+		{
+			"as is",
+			CaseAsIs,
+			[]string{
+				"billingAddress.postalCode: minimum string length is 5",
+				"first_name: minimum string length is 2",
+			},
+		},
+		{
+			"camel",
+			CaseCamel,
+			[]string{
+				"billingAddress.postalCode: minimum string length is 5",
+				"firstName: minimum string length is 2",
+			},
+		},
+		{
+			"snake",
+			CaseSnake,
+			[]string{
+				"billing_address.postal_code: minimum string length is 5",
+				"first_name: minimum string length is 2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/accounts", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:        "./fixtures/openapi.yaml",
+				FieldNameCase: tc.fieldCase,
+			}))
+
+			b := []byte(`{"first_name": "a", "billingAddress": {"postalCode": "1"}}`)
+			req := httptest.NewRequest(http.MethodPost, "/accounts", bytes.NewBuffer(b))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			j := &ValidationError{}
+			err := json.Unmarshal(resp.Body.Bytes(), j)
+			assert.NoError(t, err)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			assert.Equal(t, tc.errors, j.Errors)
+		})
+	}
+}
+
+func TestFieldNameCase_Apply(t *testing.T) {
+	testCases := []struct {
+		in    string
+		camel string
+		snake string
+	}{
+		{"name", "name", "name"},
+		{"first_name", "firstName", "first_name"},
+		{"first-name", "firstName", "first_name"},
+		{"firstName", "firstName", "first_name"},
+		{"userID", "userID", "user_id"},
+		{"IDToken", "IDToken", "id_token"},
+		{"0", "0", "0"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			assert.Equal(t, []string{tc.in}, CaseAsIs.apply([]string{tc.in}))
+			assert.Equal(t, []string{tc.camel}, CaseCamel.apply([]string{tc.in}))
+			assert.Equal(t, []string{tc.snake}, CaseSnake.apply([]string{tc.in}))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/gommon/log"
+)
+
+// RequestValidator validates requests against an OpenAPI specification
+// outside of echo, e.g. the requests a service makes to a downstream API.
+type RequestValidator struct {
+	spec *spec
+}
+
+// NewRequestValidator loads the specification set in config. Only the
+// schema source, loading options, formats and body decoders of config are
+// used.
+func NewRequestValidator(config Config) (*RequestValidator, error) {
+	if err := checkSource(config); err != nil {
+		return nil, err
+	}
+
+	registerExtensions(config)
+
+	if config.Logger == nil {
+		config.Logger = log.New("openapi")
+	}
+
+	s, _, err := loadSpec(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RequestValidator{spec: s}, nil
+}
+
+// MustNewRequestValidator is like NewRequestValidator but panics on error.
+func MustNewRequestValidator(config Config) *RequestValidator {
+	v, err := NewRequestValidator(config)
+	if err != nil {
+		panic(err.Error())
+	}
+
+	return v
+}
+
+// ValidateOutgoing validates req against the specification. The request
+// body, if any, is restored so req can still be sent.
+func (v *RequestValidator) ValidateOutgoing(req *http.Request) error {
+	_, err := v.validate(req)
+	return err
+}
+
+// validate validates req and returns the input it was validated with.
+func (v *RequestValidator) validate(req *http.Request) (*openapi3filter.RequestValidationInput, error) {
+	route, pathParams, err := v.spec.router.FindRoute(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed finding route for %s %s: %v", req.Method, req.URL.String(), err)
+	}
+
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}
+
+	err = openapi3filter.ValidateRequest(req.Context(), input)
+	switch err := err.(type) {
+	case nil:
+	case openapi3.MultiError:
+		return nil, fmt.Errorf("failed validating request: %s", strings.Join(flattenIssues(convertError(err, CaseAsIs)), "; "))
+	default:
+		return nil, fmt.Errorf("failed validating request: %v", err)
+	}
+
+	return input, nil
+}
@@ -0,0 +1,130 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRequestValidator_Error(t *testing.T) {
+	v, err := NewRequestValidator(Config{})
+	assert.EqualError(t, err, "either spec, schema, schemaBytes, schemaReader or schemaURL is required")
+	assert.Nil(t, v)
+
+	v, err = NewRequestValidator(Config{Schema: "./fixtures/invalid.yaml"})
+	assert.Error(t, err)
+	assert.Nil(t, v)
+}
+
+func TestMustNewRequestValidator_Panics(t *testing.T) {
+	assert.Panics(t, func() { MustNewRequestValidator(Config{}) })
+	assert.Panics(t, func() { MustNewRequestValidator(Config{Schema: "./fixtures/invalid.yaml"}) })
+}
+
+func TestRequestValidator_ValidateOutgoing(t *testing.T) {
+	v := MustNewRequestValidator(Config{Schema: "./fixtures/openapi.yaml"})
+
+	testCases := []struct {
+		name   string
+		method string
+		url    string
+		body   string
+		err    string
+	}{
+		{"valid", http.MethodPost, "http://downstream/validation", `{"username": "test"}`, ""},
+		{
+			"invalid body",
+			http.MethodPost,
+			"http://downstream/validation",
+			`{"username": "a"}`,
+			"failed validating request: username: minimum string length is 2",
+		},
+		{
+			"invalid query",
+			http.MethodPost,
+			"http://downstream/validation/test?limit=200",
+			"",
+			"failed validating request: parameter 'limit' in query has an error: number must be at most 100",
+		},
+		{
+			"path not found",
+			http.MethodGet,
+			"http://downstream/notfound",
+			"",
+			"failed finding route for GET http://downstream/notfound: no matching operation was found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			assert.NoError(t, err)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			err = v.ValidateOutgoing(req)
+			if tc.err == "" {
+				assert.NoError(t, err)
+
+				b, err := io.ReadAll(req.Body)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.body, string(b))
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+const clientFormatsSpec = `
+openapi: 3.0.4
+info:
+  version: 1.0.0
+  title: Client Formats API
+paths:
+  /codes:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                code:
+                  type: string
+                  format: client-code
+      responses:
+        '200':
+          description: Successful response
+`
+
+func TestNewRequestValidator_Formats(t *testing.T) {
+	v, err := NewRequestValidator(Config{
+		SchemaBytes: []byte(clientFormatsSpec),
+		Formats: map[string]func(string) error{
+			"client-code": func(value string) error {
+				if !strings.HasPrefix(value, "C-") {
+					return errors.New("invalid client code")
+				}
+				return nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	newRequest := func(body string) *http.Request {
+		req, err := http.NewRequest(http.MethodPost, "http://downstream/codes", bytes.NewBufferString(body))
+		assert.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		return req
+	}
+
+	assert.NoError(t, v.ValidateOutgoing(newRequest(`{"code": "C-1"}`)))
+	assert.Error(t, v.ValidateOutgoing(newRequest(`{"code": "1"}`)))
+}
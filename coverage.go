@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+)
+
+// Coverage records which operations of a spec, and which of their
+// documented responses, were exercised by the requests the middleware
+// handled, e.g. to fail CI when an end-to-end suite misses some. It's safe
+// for concurrent use.
+type Coverage struct {
+	mu   sync.Mutex
+	doc  *openapi3.T
+	hits map[coverageKey]*operationHits
+}
+
+// coverageKey identifies an operation by method and spec path.
+type coverageKey struct {
+	method string
+	path   string
+}
+
+// operationHits counts the requests to an operation and its responses,
+// keyed like the responses of the spec, e.g. "200", "4XX" or "default".
+type operationHits struct {
+	requests  int
+	responses map[string]int
+}
+
+// NewCoverage creates an empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{hits: make(map[coverageKey]*operationHits)}
+}
+
+// CoverageReport lists the operations of a spec with how often they and
+// their documented responses were exercised.
+type CoverageReport struct {
+	Operations []OperationCoverage
+}
+
+// OperationCoverage is the coverage of an operation.
+type OperationCoverage struct {
+	Method      string
+	Path        string
+	OperationID string
+
+	// Hits is the number of requests matching the operation.
+	Hits int
+
+	// Responses is the number of responses sent for each documented
+	// response, keyed like in the spec, e.g. "200", "4XX" or "default".
+	Responses map[string]int
+
+	// Undocumented is the number of responses sent with statuses the
+	// operation doesn't document, keyed by status.
+	Undocumented map[int]int
+}
+
+// Uncovered returns the operations that were never exercised.
+func (r CoverageReport) Uncovered() []OperationCoverage {
+	var ops []OperationCoverage
+	for _, op := range r.Operations {
+		if op.Hits == 0 {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// UncoveredResponses returns the sorted documented responses of the
+// operation that were never sent.
+func (o OperationCoverage) UncoveredResponses() []string {
+	var responses []string
+	for status, hits := range o.Responses {
+		if hits == 0 {
+			responses = append(responses, status)
+		}
+	}
+	sort.Strings(responses)
+	return responses
+}
+
+// Report returns the coverage of the operations of the last spec loaded,
+// sorted by path and method.
+func (cov *Coverage) Report() CoverageReport {
+	cov.mu.Lock()
+	defer cov.mu.Unlock()
+
+	var report CoverageReport
+	if cov.doc == nil || cov.doc.Paths == nil {
+		return report
+	}
+
+	for path, pathItem := range cov.doc.Paths.Map() {
+		for method, op := range pathItem.Operations() {
+			oc := OperationCoverage{
+				Method:       method,
+				Path:         path,
+				OperationID:  op.OperationID,
+				Responses:    make(map[string]int),
+				Undocumented: make(map[int]int),
+			}
+			if op.Responses != nil {
+				for status := range op.Responses.Map() {
+					oc.Responses[status] = 0
+				}
+			}
+
+			if hits, ok := cov.hits[coverageKey{method, path}]; ok {
+				oc.Hits = hits.requests
+				for status, n := range hits.responses {
+					if _, ok := oc.Responses[status]; ok {
+						oc.Responses[status] = n
+					} else if code, err := strconv.Atoi(status); err == nil {
+						oc.Undocumented[code] = n
+					}
+				}
+			}
+
+			report.Operations = append(report.Operations, oc)
+		}
+	}
+
+	sort.Slice(report.Operations, func(i, j int) bool {
+		a, b := report.Operations[i], report.Operations[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return a.Method < b.Method
+	})
+
+	return report
+}
+
+// setSpec sets the spec whose operations are reported.
+func (cov *Coverage) setSpec(doc *openapi3.T) {
+	cov.mu.Lock()
+	defer cov.mu.Unlock()
+
+	cov.doc = doc
+}
+
+// record records a request matching route that was responded with status.
+func (cov *Coverage) record(route *routers.Route, status int) {
+	cov.mu.Lock()
+	defer cov.mu.Unlock()
+
+	key := coverageKey{route.Method, route.Path}
+	hits, ok := cov.hits[key]
+	if !ok {
+		hits = &operationHits{responses: make(map[string]int)}
+		cov.hits[key] = hits
+	}
+
+	hits.requests++
+	hits.responses[documentedResponse(route, status)]++
+}
+
+// documentedResponse returns the key of the response route documents for
+// status, or status itself if it documents none.
+func documentedResponse(route *routers.Route, status int) string {
+	s := strconv.Itoa(status)
+	if route.Operation.Responses == nil {
+		return s
+	}
+
+	for _, key := range []string{s, s[:1] + "XX", "default"} {
+		if route.Operation.Responses.Value(key) != nil {
+			return key
+		}
+	}
+	return s
+}
+
+// responseStatus returns the status of the response of c to a request
+// handled with err.
+func responseStatus(c echo.Context, err error) int {
+	if c.Response().Committed {
+		return c.Response().Status
+	}
+
+	var he *echo.HTTPError
+	if errors.As(err, &he) {
+		return he.Code
+	}
+	if err != nil {
+		return http.StatusInternalServerError
+	}
+	return c.Response().Status
+}
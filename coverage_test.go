@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const coverageSpec = `
+openapi: 3.0.4
+info:
+  title: Coverage API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      operationId: listItems
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+        '4XX':
+          description: Client error
+    post:
+      operationId: createItem
+      responses:
+        '201':
+          description: Created
+        default:
+          description: Error
+  /items/{id}:
+    get:
+      operationId: getItem
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: OK
+        '404':
+          description: Not found
+`
+
+func TestOpenAPIWithConfig_Coverage(t *testing.T) {
+	e := echo.New()
+	coverage := NewCoverage()
+
+	e.GET("/items", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+	e.GET("/items/:id", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusInternalServerError)
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		SchemaBytes: []byte(coverageSpec),
+		Coverage:    coverage,
+	}))
+
+	assert.Len(t, coverage.Report().Uncovered(), 3)
+
+	for _, target := range []string{"/items", "/items?limit=abc", "/items/1"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+	}
+
+	report := coverage.Report()
+
+	assert.Equal(t, []OperationCoverage{
+		{
+			Method: http.MethodGet, Path: "/items", OperationID: "listItems", Hits: 2,
+			Responses: map[string]int{"200": 1, "4XX": 1}, Undocumented: map[int]int{},
+		},
+		{
+			Method: http.MethodPost, Path: "/items", OperationID: "createItem", Hits: 0,
+			Responses: map[string]int{"201": 0, "default": 0}, Undocumented: map[int]int{},
+		},
+		{
+			Method: http.MethodGet, Path: "/items/{id}", OperationID: "getItem", Hits: 1,
+			Responses: map[string]int{"200": 0, "404": 0}, Undocumented: map[int]int{500: 1},
+		},
+	}, report.Operations)
+
+	uncovered := report.Uncovered()
+	assert.Len(t, uncovered, 1)
+	assert.Equal(t, "createItem", uncovered[0].OperationID)
+
+	assert.Empty(t, report.Operations[0].UncoveredResponses())
+	assert.Equal(t, []string{"200", "404"}, report.Operations[2].UncoveredResponses())
+}
+
+func TestCoverage_Report_Empty(t *testing.T) {
+	assert.Empty(t, NewCoverage().Report().Operations)
+}
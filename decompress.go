@@ -0,0 +1,63 @@
+package openapi
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// decompressedBody is a decompressed request body, closing the original.
+type decompressedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// decompressBody replaces the gzip or deflate encoded body of req by its
+// decompressed content, reporting whether it did. The Content-Encoding and
+// Content-Length headers are removed, as they no longer apply. Deflate
+// bodies are accepted both zlib wrapped, as specified, and raw, as sent by
+// some clients. Bodies in other encodings are left as is.
+func decompressBody(req *http.Request) (bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return false, nil
+	}
+
+	var (
+		r   io.Reader
+		err error
+	)
+	switch strings.ToLower(strings.TrimSpace(req.Header.Get(echo.HeaderContentEncoding))) {
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(req.Body)
+	case "deflate":
+		br := bufio.NewReader(req.Body)
+		if header, _ := br.Peek(2); isZlibHeader(header) {
+			r, err = zlib.NewReader(br)
+		} else {
+			r = flate.NewReader(br)
+		}
+	default:
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	req.Body = decompressedBody{Reader: r, Closer: req.Body}
+	req.ContentLength = -1
+	req.Header.Del(echo.HeaderContentEncoding)
+	req.Header.Del(echo.HeaderContentLength)
+
+	return true, nil
+}
+
+// isZlibHeader reports whether b starts with a zlib header using deflate.
+func isZlibHeader(b []byte) bool {
+	return len(b) == 2 && b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
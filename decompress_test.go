@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func compress(t *testing.T, encoding string, s string) []byte {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+		err error
+	)
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw deflate":
+		w, err = flate.NewWriter(&buf, flate.DefaultCompression)
+		assert.NoError(t, err)
+	}
+
+	_, err = w.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	return buf.Bytes()
+}
+
+func TestOpenAPIWithConfig_DecompressBodies(t *testing.T) {
+	valid := `{"username":"test"}`
+	large := `{"username":"` + strings.Repeat("x", 2048) + `"}`
+
+	testCases := []struct {
+		name                 string
+		decompress           bool
+		maxDecompressedBytes int64
+		encoding             string
+		body                 []byte
+		statusCode           int
+		errors               []string
+	}{
+		{"gzip", true, 0, "gzip", compress(t, "gzip", valid), http.StatusOK, nil},
+		{"deflate", true, 0, "deflate", compress(t, "deflate", valid), http.StatusOK, nil},
+		{"raw deflate", true, 0, "deflate", compress(t, "raw deflate", valid), http.StatusOK, nil},
+		{"identity", true, 0, "identity", []byte(valid), http.StatusOK, nil},
+		{"invalid content", true, 0, "gzip", compress(t, "gzip", `{"username":1}`), http.StatusUnprocessableEntity, []string{"username: value must be a string"}},
+		{"invalid gzip", true, 0, "gzip", []byte(valid), http.StatusBadRequest, []string{"request body has an error: failed to decompress request body: gzip: invalid header"}},
+		{"exceeds cap", true, 1024, "gzip", compress(t, "gzip", large), http.StatusRequestEntityTooLarge, []string{"request body exceeds 1024 bytes"}},
+		{"disabled", false, 0, "gzip", compress(t, "gzip", valid), http.StatusBadRequest, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var (
+				received string
+				encoding string
+			)
+			e.POST("/optional-body", func(c echo.Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				received = string(b)
+				encoding = c.Request().Header.Get(echo.HeaderContentEncoding)
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:               "./fixtures/openapi.yaml",
+				DecompressBodies:     tc.decompress,
+				MaxDecompressedBytes: tc.maxDecompressedBytes,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/optional-body", bytes.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set(echo.HeaderContentEncoding, tc.encoding)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			if tc.statusCode == http.StatusOK {
+				assert.Equal(t, valid, received)
+				if tc.encoding != "identity" {
+					assert.Empty(t, encoding)
+				}
+			}
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
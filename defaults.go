@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// missingParameterDefaults returns the defaults of the query, header and
+// cookie parameters of route missing from req, keyed like issues, e.g.
+// "query.limit". These are the defaults openapi3filter sets on req.
+func missingParameterDefaults(req *http.Request, route *routers.Route) map[string]any {
+	params := append(openapi3.Parameters(nil), route.PathItem.Parameters...)
+	params = append(params, route.Operation.Parameters...)
+
+	defaults := make(map[string]any)
+	for _, ref := range params {
+		param := ref.Value
+		if param == nil || param.Schema == nil || param.Schema.Value == nil {
+			continue
+		}
+
+		value := schemaDefault(param.Schema.Value)
+		if value == nil || isParameterPresent(req, param) {
+			continue
+		}
+
+		defaults[fmt.Sprintf("%s.%s", param.In, param.Name)] = value
+	}
+	return defaults
+}
+
+// schemaDefault returns the default of schema, or of its first allOf schema
+// declaring one.
+func schemaDefault(schema *openapi3.Schema) any {
+	if schema.Default != nil {
+		return schema.Default
+	}
+	for _, ref := range schema.AllOf {
+		if ref.Value != nil && ref.Value.Default != nil {
+			return ref.Value.Default
+		}
+	}
+	return nil
+}
+
+// isParameterPresent reports whether req has the query, header or cookie
+// parameter param. Path parameters are always present.
+func isParameterPresent(req *http.Request, param *openapi3.Parameter) bool {
+	switch param.In {
+	case openapi3.ParameterInQuery:
+		return req.URL.Query().Has(param.Name)
+	case openapi3.ParameterInHeader:
+		return len(req.Header.Values(param.Name)) > 0
+	case openapi3.ParameterInCookie:
+		_, err := req.Cookie(param.Name)
+		return err == nil
+	}
+	return true
+}
@@ -0,0 +1,74 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_SchemaDefaults(t *testing.T) {
+	testCases := []struct {
+		name         string
+		skipDefaults bool
+		target       string
+		header       http.Header
+		body         string
+		lang         string
+		timezone     string
+		expectedBody string
+		defaults     any
+	}{
+		{
+			"defaults", false, "/preferences", http.Header{}, `{}`,
+			"en", "UTC", `{"pageSize":20,"theme":"light"}`,
+			map[string]any{"query.lang": "en", "header.X-Timezone": "UTC"},
+		},
+		{
+			"provided", false, "/preferences?lang=fr", http.Header{"X-Timezone": {"CET"}}, `{"theme":"dark","pageSize":50}`,
+			"fr", "CET", `{"theme":"dark","pageSize":50}`,
+			map[string]any{},
+		},
+		{
+			"skip defaults", true, "/preferences", http.Header{}, `{}`,
+			"", "", `{}`,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/preferences", func(c echo.Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tc.lang, c.QueryParam("lang"))
+				assert.Equal(t, tc.timezone, c.Request().Header.Get("X-Timezone"))
+				assert.JSONEq(t, tc.expectedBody, string(b))
+				assert.Equal(t, tc.defaults, c.Get("defaults"))
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:             "./fixtures/openapi.yaml",
+				SkipDefaults:       tc.skipDefaults,
+				DefaultsContextKey: "defaults",
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(tc.body))
+			req.Header = tc.header
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// formatDiagnostics renders me as a human-readable, multi-line report meant
+// for local development.
+func formatDiagnostics(msg string, me openapi3.MultiError, fieldCase FieldNameCase) string {
+	var sb strings.Builder
+	sb.WriteString(msg)
+	sb.WriteString("\n")
+
+	for _, fe := range collectFieldErrors(me, fieldCase) {
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("  %s\n", fe.path()))
+		if fe.Code != "" {
+			sb.WriteString(fmt.Sprintf("    constraint: %s\n", fe.Code))
+		}
+		if fe.Value != nil {
+			b, err := json.Marshal(fe.Value)
+			if err != nil {
+				b = []byte(fmt.Sprintf("%v", fe.Value))
+			}
+			sb.WriteString(fmt.Sprintf("    received:   %s\n", b))
+		}
+		sb.WriteString(fmt.Sprintf("    message:    %s\n", fe.Message))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,38 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_DevMode(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation/:username", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:  "./fixtures/openapi.yaml",
+		DevMode: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation/a?limit=200", bytes.NewBuffer([]byte(`{}`)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Header().Get(echo.HeaderContentType), echo.MIMETextPlain)
+
+	body := resp.Body.String()
+	assert.Contains(t, body, "Validation error\n")
+	assert.Contains(t, body, "  path.username\n    constraint: minLength\n    received:   \"a\"\n    message:    minimum string length is 2\n")
+	assert.Contains(t, body, "  query.limit\n    constraint: maximum\n    received:   200\n    message:    number must be at most 100\n")
+}
@@ -0,0 +1,125 @@
+package openapi
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+)
+
+// DocsUI is the interactive documentation UI rendered by DocsHandler.
+type DocsUI int
+
+const (
+	// SwaggerUI renders the documentation with Swagger UI.
+	SwaggerUI DocsUI = iota
+	// ReDoc renders the documentation with ReDoc.
+	ReDoc
+	// Elements renders the documentation with Stoplight Elements.
+	Elements
+)
+
+// DocsConfig defines the config for DocsHandler.
+type DocsConfig struct {
+	// UI defines the UI rendering the documentation. Its assets are loaded
+	// from public CDNs.
+	// Optional. Defaults to SwaggerUI.
+	UI DocsUI
+
+	// Title defines the title of the page.
+	// Optional. Defaults to the title of the spec.
+	Title string
+
+	// SpecURL defines the URL the UI loads the spec from, e.g. the
+	// SpecPath of the middleware, so it follows reloads. When unset, the
+	// spec is embedded in the page.
+	// Optional.
+	SpecURL string
+}
+
+var docsTemplates = map[DocsUI]*template.Template{
+	SwaggerUI: template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+SwaggerUIBundle({ {{if .SpecURL}}url: {{.SpecURL}}{{else}}spec: {{.Spec}}{{end}}, dom_id: "#swagger-ui" });
+</script>
+</body>
+</html>
+`)),
+	ReDoc: template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body>
+<div id="redoc"></div>
+<script src="https://cdn.redoc.ly/redoc/latest/bundles/redoc.standalone.js"></script>
+<script>
+Redoc.init({{if .SpecURL}}{{.SpecURL}}{{else}}{{.Spec}}{{end}}, {}, document.getElementById("redoc"));
+</script>
+</body>
+</html>
+`)),
+	Elements: template.Must(template.New("elements").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/@stoplight/elements/styles.min.css">
+<script src="https://unpkg.com/@stoplight/elements/web-components.min.js"></script>
+</head>
+<body>
+<elements-api id="docs" router="hash" layout="sidebar"></elements-api>
+<script>
+const docs = document.getElementById("docs");
+{{if .SpecURL}}docs.apiDescriptionUrl = {{.SpecURL}};{{else}}docs.apiDescriptionDocument = {{.Spec}};{{end}}
+</script>
+</body>
+</html>
+`)),
+}
+
+// DocsHandler returns a handler serving an interactive documentation page
+// of spec, e.g. the Spec of the middleware, rendered by the UI of config.
+// spec may be nil if config.SpecURL is set.
+func DocsHandler(spec *openapi3.T, config DocsConfig) echo.HandlerFunc {
+	if spec == nil && config.SpecURL == "" {
+		panic("either spec or specURL is required")
+	}
+
+	tmpl, ok := docsTemplates[config.UI]
+	if !ok {
+		panic("unsupported docs ui")
+	}
+
+	title := config.Title
+	if title == "" && spec != nil && spec.Info != nil {
+		title = spec.Info.Title
+	}
+
+	var buf bytes.Buffer
+	err := tmpl.Execute(&buf, struct {
+		Title   string
+		SpecURL string
+		Spec    *openapi3.T
+	}{title, config.SpecURL, spec})
+	if err != nil {
+		panic(err.Error())
+	}
+	page := buf.Bytes()
+
+	return func(c echo.Context) error {
+		return c.HTMLBlob(http.StatusOK, page)
+	}
+}
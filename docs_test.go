@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocsHandler(t *testing.T) {
+	spec, err := openapi3.NewLoader().LoadFromFile("./fixtures/openapi.yaml")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		spec     *openapi3.T
+		config   DocsConfig
+		contains []string
+	}{
+		{"swagger ui", spec, DocsConfig{}, []string{"<title>Test API</title>", "swagger-ui-bundle.js", `spec: {`, `"title":"Test API"`}},
+		{"redoc", spec, DocsConfig{UI: ReDoc}, []string{"redoc.standalone.js", `Redoc.init({`, `"title":"Test API"`}},
+		{"elements", spec, DocsConfig{UI: Elements}, []string{"<elements-api", `docs.apiDescriptionDocument = {`}},
+		{"spec url", nil, DocsConfig{SpecURL: "/openapi.json", Title: "Docs"}, []string{"<title>Docs</title>", `url: "/openapi.json"`}},
+		{"escaped title", spec, DocsConfig{Title: "<script>"}, []string{"<title>&lt;script&gt;</title>"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/docs", DocsHandler(tc.spec, tc.config))
+
+			req := httptest.NewRequest(http.MethodGet, "/docs", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, echo.MIMETextHTMLCharsetUTF8, resp.Header().Get(echo.HeaderContentType))
+			for _, s := range tc.contains {
+				assert.Contains(t, resp.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestDocsHandler_Panics(t *testing.T) {
+	assert.PanicsWithValue(t, "either spec or specURL is required", func() { DocsHandler(nil, DocsConfig{}) })
+	assert.PanicsWithValue(t, "unsupported docs ui", func() { DocsHandler(nil, DocsConfig{UI: DocsUI(42), SpecURL: "/openapi.json"}) })
+}
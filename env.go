@@ -0,0 +1,64 @@
+package openapi
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// ConfigFromEnv returns DefaultConfig with the fields set by the following
+// environment variables, each name starting with prefix and an underscore,
+// e.g. "OPENAPI_SCHEMA" for the prefix "OPENAPI":
+//
+//	SCHEMA         Schema
+//	SCHEMA_URL     SchemaURL
+//	POLL_INTERVAL  PollInterval, e.g. "30s"
+//	CONTEXT_KEY    ContextKey
+//	DEV_MODE       DevMode, e.g. "true"
+//	MOCK_MODE      MockMode, e.g. "true"
+//
+// Unset or empty variables keep the default. It panics on invalid values.
+func ConfigFromEnv(prefix string) Config {
+	c := DefaultConfig
+
+	lookup := func(name string) (string, string, bool) {
+		if prefix != "" {
+			name = prefix + "_" + name
+		}
+		v := os.Getenv(name)
+		return name, v, v != ""
+	}
+
+	if _, v, ok := lookup("SCHEMA"); ok {
+		c.Schema = v
+	}
+
+	if _, v, ok := lookup("SCHEMA_URL"); ok {
+		c.SchemaURL = v
+	}
+
+	if name, v, ok := lookup("POLL_INTERVAL"); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			panic(fmt.Sprintf("invalid value for %s: %v", name, err))
+		}
+		c.PollInterval = d
+	}
+
+	if _, v, ok := lookup("CONTEXT_KEY"); ok {
+		c.ContextKey = v
+	}
+
+	for key, field := range map[string]*bool{"DEV_MODE": &c.DevMode, "MOCK_MODE": &c.MockMode} {
+		if name, v, ok := lookup(key); ok {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				panic(fmt.Sprintf("invalid value for %s: %v", name, err))
+			}
+			*field = b
+		}
+	}
+
+	return c
+}
@@ -0,0 +1,69 @@
+package openapi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("OPENAPI_SCHEMA", "./fixtures/openapi.yaml")
+	t.Setenv("OPENAPI_SCHEMA_URL", "http://localhost/openapi.yaml")
+	t.Setenv("OPENAPI_POLL_INTERVAL", "30s")
+	t.Setenv("OPENAPI_CONTEXT_KEY", "openapi")
+	t.Setenv("OPENAPI_DEV_MODE", "true")
+	t.Setenv("OPENAPI_MOCK_MODE", "1")
+
+	c := ConfigFromEnv("OPENAPI")
+
+	assert.Equal(t, "./fixtures/openapi.yaml", c.Schema)
+	assert.Equal(t, "http://localhost/openapi.yaml", c.SchemaURL)
+	assert.Equal(t, 30*time.Second, c.PollInterval)
+	assert.Equal(t, "openapi", c.ContextKey)
+	assert.True(t, c.DevMode)
+	assert.True(t, c.MockMode)
+	assert.NotNil(t, c.Skipper)
+	assert.Equal(t, DefaultConfig.MissingBodyMessage, c.MissingBodyMessage)
+}
+
+func TestConfigFromEnv_Unset(t *testing.T) {
+	t.Setenv("OPENAPI_SCHEMA", "")
+
+	c := ConfigFromEnv("OPENAPI")
+
+	assert.Equal(t, "", c.Schema)
+	assert.Equal(t, "", c.SchemaURL)
+	assert.Equal(t, time.Duration(0), c.PollInterval)
+	assert.Equal(t, DefaultConfig.ContextKey, c.ContextKey)
+	assert.False(t, c.DevMode)
+	assert.False(t, c.MockMode)
+}
+
+func TestConfigFromEnv_No_Prefix(t *testing.T) {
+	t.Setenv("SCHEMA", "./fixtures/openapi.yaml")
+
+	c := ConfigFromEnv("")
+
+	assert.Equal(t, "./fixtures/openapi.yaml", c.Schema)
+}
+
+func TestConfigFromEnv_Invalid_Panics(t *testing.T) {
+	testCases := []struct {
+		name  string
+		key   string
+		value string
+		msg   string
+	}{
+		{"poll interval", "OPENAPI_POLL_INTERVAL", "often", `invalid value for OPENAPI_POLL_INTERVAL: time: invalid duration "often"`},
+		{"dev mode", "OPENAPI_DEV_MODE", "yes", `invalid value for OPENAPI_DEV_MODE: strconv.ParseBool: parsing "yes": invalid syntax`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(tc.key, tc.value)
+
+			assert.PanicsWithValue(t, tc.msg, func() { ConfigFromEnv("OPENAPI") })
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package openapi
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// ErrorDetail defines how much detail validation error responses contain.
+type ErrorDetail int
+
+const (
+	// ErrorDetailBasic returns errors as a list of messages.
+	ErrorDetailBasic ErrorDetail = iota
+	// ErrorDetailFull also returns a FieldError for each issue.
+	ErrorDetailFull
+	// ErrorDetailStructured returns a FieldError for each issue instead of
+	// the messages, falling back to the messages for issues without any.
+	ErrorDetailStructured
+)
+
+// fields reports whether d returns a FieldError for each issue.
+func (d ErrorDetail) fields() bool {
+	return d == ErrorDetailFull || d == ErrorDetailStructured
+}
+
+// FieldError describes a single validation issue.
+type FieldError struct {
+	// Field is the JSON pointer of the invalid value for the request body,
+	// or the name of the parameter.
+	Field string `json:"field"`
+
+	// In is where the field is located: body, query, path, header or cookie.
+	In string `json:"in"`
+
+	// Code is the JSON schema keyword that failed, e.g. "minLength".
+	Code string `json:"code"`
+
+	// Message describes the issue.
+	Message string `json:"message"`
+
+	// Value is the value that failed validation, if known.
+	Value any `json:"value,omitempty"`
+}
+
+// path returns the dot-separated location of the field, e.g. "body.user.name".
+func (fe FieldError) path() string {
+	if fe.In == "" {
+		return "unknown"
+	}
+
+	field := strings.ReplaceAll(strings.TrimPrefix(fe.Field, "/"), "/", ".")
+	if field == "" {
+		return fe.In
+	}
+	return fe.In + "." + field
+}
+
+// collectFieldErrors flattens me into a list of FieldError.
+func collectFieldErrors(me openapi3.MultiError, fieldCase FieldNameCase) []FieldError {
+	return collectFieldErrorsAt(me, fieldCase, nil)
+}
+
+// collectFieldErrorsAt flattens me, prefixing schema error paths with prefix.
+func collectFieldErrorsAt(me openapi3.MultiError, fieldCase FieldNameCase, prefix []string) []FieldError {
+	var fes []FieldError
+	for _, err := range me {
+		switch err := err.(type) {
+		case *openapi3.SchemaError:
+			path := append(append([]string(nil), prefix...), err.JSONPointer()...)
+			if origin := schemaErrorOrigin(err); origin != nil {
+				fes = append(fes, collectFieldErrorsAt(origin, fieldCase, path)...)
+				continue
+			}
+			fes = append(fes, schemaFieldError(err, fieldCase, path))
+		case *openapi3filter.RequestError:
+			if err.Parameter != nil {
+				fe := FieldError{
+					Field:   err.Parameter.Name,
+					In:      err.Parameter.In,
+					Code:    "invalid",
+					Message: parameterErrorReason(err.Err),
+				}
+
+				var se *openapi3.SchemaError
+				var pe *openapi3filter.ParseError
+				if errors.As(err.Err, &se) {
+					fe.Code = se.SchemaField
+					fe.Value = se.Value
+					fe.Message = strings.ReplaceAll(se.Reason, "\"", "'")
+				} else if errors.As(err.Err, &pe) && pe.Value != nil {
+					fe.Code = "type"
+					fe.Value = pe.Value
+				}
+
+				fes = append(fes, fe)
+				continue
+			}
+
+			if me := asMultiError(err.Err); me != nil {
+				fes = append(fes, collectFieldErrorsAt(me, fieldCase, prefix)...)
+				continue
+			}
+
+			if err.RequestBody != nil {
+				code := "invalid"
+				if errors.Is(err.Err, openapi3filter.ErrInvalidRequired) {
+					code = "required"
+				}
+				fes = append(fes, FieldError{In: "body", Code: code, Message: err.Error()})
+				continue
+			}
+		default:
+			fes = append(fes, FieldError{Code: "unknown", Message: err.Error()})
+		}
+	}
+	return fes
+}
+
+func schemaFieldError(err *openapi3.SchemaError, fieldCase FieldNameCase, path []string) FieldError {
+	fe := FieldError{
+		In:      "body",
+		Code:    err.SchemaField,
+		Message: strings.ReplaceAll(schemaErrorReason(err), "\"", "'"),
+	}
+
+	if len(path) > 0 {
+		fe.Field = "/" + strings.Join(fieldCase.apply(path), "/")
+	}
+
+	// the value of a missing property is its parent object
+	if err.SchemaField != "required" {
+		fe.Value = err.Value
+	}
+
+	return fe
+}
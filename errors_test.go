@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_ErrorDetail_Full(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation/:username", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		ErrorDetail: ErrorDetailFull,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation/a?limit=200", bytes.NewBufferString(`{"username": "a"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set("x-username", "a")
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Len(t, j.Errors, 4)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "/username", In: "body", Code: "minLength", Message: "minimum string length is 2", Value: "a"},
+		{Field: "limit", In: "query", Code: "maximum", Message: "number must be at most 100", Value: float64(200)},
+		{Field: "username", In: "path", Code: "minLength", Message: "minimum string length is 2", Value: "a"},
+		{Field: "x-username", In: "header", Code: "minLength", Message: "minimum string length is 2", Value: "a"},
+	}, j.Details)
+}
+
+func TestOpenAPIWithConfig_ErrorDetail_Full_Missing_Body(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:             "./fixtures/openapi.yaml",
+		ErrorDetail:        ErrorDetailFull,
+		MissingBodyMessage: "Request body is required",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, []FieldError{{In: "body", Code: "required", Message: "Request body is required"}}, j.Details)
+}
+
+func TestOpenAPIWithConfig_ErrorDetail_Basic(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation/:username", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation/a", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "details")
+}
+
+func TestOpenAPIWithConfig_ErrorDetail_Structured(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation/:username", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		ErrorDetail: ErrorDetailStructured,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation/test?limit=200", bytes.NewBufferString(`{"username": "a"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Empty(t, j.Errors)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "/username", In: "body", Code: "minLength", Message: "minimum string length is 2", Value: "a"},
+		{Field: "limit", In: "query", Code: "maximum", Message: "number must be at most 100", Value: float64(200)},
+	}, j.Details)
+}
+
+func TestOpenAPIWithConfig_ErrorDetail_Structured_Fallback(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/basic", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		ErrorDetail: ErrorDetailStructured,
+		BasicAuthValidator: func(username string, password string, c echo.Context) (bool, error) {
+			return true, nil
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/basic", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+	assert.Equal(t, []string{"missing basic credentials"}, j.Errors)
+	assert.Empty(t, j.Details)
+}
@@ -0,0 +1,169 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// validateExamples validates every example of doc against its schema,
+// returning an error listing all the mismatches with their location.
+func validateExamples(doc *openapi3.T) error {
+	v := &examplesValidator{visited: make(map[*openapi3.Schema]bool)}
+
+	if doc.Components != nil {
+		for _, name := range sortedKeys(doc.Components.Schemas) {
+			v.schema("components.schemas."+name, doc.Components.Schemas[name])
+		}
+	}
+
+	if doc.Paths != nil {
+		for _, path := range doc.Paths.InMatchingOrder() {
+			pathItem := doc.Paths.Value(path)
+			loc := "paths." + path
+			for _, ref := range pathItem.Parameters {
+				v.parameter(loc, ref)
+			}
+
+			operations := pathItem.Operations()
+			for _, method := range sortedKeys(operations) {
+				op := operations[method]
+				loc := loc + "." + strings.ToLower(method)
+				for _, ref := range op.Parameters {
+					v.parameter(loc, ref)
+				}
+				if op.RequestBody != nil && op.RequestBody.Value != nil {
+					v.content(loc+".requestBody", op.RequestBody.Value.Content, openapi3.VisitAsRequest())
+				}
+				if op.Responses != nil {
+					responses := op.Responses.Map()
+					for _, status := range sortedKeys(responses) {
+						ref := responses[status]
+						if ref == nil || ref.Value == nil {
+							continue
+						}
+						loc := loc + ".responses." + status
+						v.content(loc, ref.Value.Content, openapi3.VisitAsResponse())
+						for _, name := range sortedKeys(ref.Value.Headers) {
+							if h := ref.Value.Headers[name]; h != nil && h.Value != nil {
+								v.parameterExamples(loc+".headers."+name, &h.Value.Parameter)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if len(v.errs) > 0 {
+		return fmt.Errorf("invalid examples: %s", strings.Join(v.errs, "; "))
+	}
+	return nil
+}
+
+// examplesValidator collects the example mismatches of a document.
+type examplesValidator struct {
+	visited map[*openapi3.Schema]bool
+	errs    []string
+}
+
+// check validates value, the example at loc, against schema.
+func (v *examplesValidator) check(loc string, schema *openapi3.SchemaRef, value any, opts ...openapi3.SchemaValidationOption) {
+	if schema == nil || schema.Value == nil || value == nil {
+		return
+	}
+
+	opts = append(opts, openapi3.MultiErrors())
+	err := schema.Value.VisitJSON(value, opts...)
+	if err == nil {
+		return
+	}
+
+	me := asMultiError(err)
+	if me == nil {
+		v.errs = append(v.errs, fmt.Sprintf("%s: %v", loc, err))
+		return
+	}
+	for _, msg := range flattenIssues(convertError(me, CaseAsIs)) {
+		v.errs = append(v.errs, fmt.Sprintf("%s: %s", loc, msg))
+	}
+}
+
+// schema validates the examples of the schema at loc and its subschemas.
+func (v *examplesValidator) schema(loc string, ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil || v.visited[ref.Value] {
+		return
+	}
+	s := ref.Value
+	v.visited[s] = true
+
+	v.check(loc+".example", ref, s.Example)
+
+	for _, name := range sortedKeys(s.Properties) {
+		v.schema(loc+".properties."+name, s.Properties[name])
+	}
+	v.schema(loc+".items", s.Items)
+	v.schema(loc+".additionalProperties", s.AdditionalProperties.Schema)
+	for _, item := range s.AllOf {
+		v.schema(loc+".allOf", item)
+	}
+	for _, item := range s.AnyOf {
+		v.schema(loc+".anyOf", item)
+	}
+	for _, item := range s.OneOf {
+		v.schema(loc+".oneOf", item)
+	}
+}
+
+// parameter validates the examples of the parameter at loc.
+func (v *examplesValidator) parameter(loc string, ref *openapi3.ParameterRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	v.parameterExamples(loc+".parameters."+ref.Value.Name, ref.Value)
+}
+
+// parameterExamples validates the examples of the parameter or header p at
+// loc.
+func (v *examplesValidator) parameterExamples(loc string, p *openapi3.Parameter) {
+	v.check(loc+".example", p.Schema, p.Example)
+	v.examples(loc, p.Schema, p.Examples)
+	v.schema(loc+".schema", p.Schema)
+	v.content(loc, p.Content)
+}
+
+// content validates the examples of the media types of content at loc.
+func (v *examplesValidator) content(loc string, content openapi3.Content, opts ...openapi3.SchemaValidationOption) {
+	for _, name := range sortedKeys(content) {
+		mt := content[name]
+		if mt == nil {
+			continue
+		}
+		loc := loc + ".content." + name
+
+		v.check(loc+".example", mt.Schema, mt.Example, opts...)
+		v.examples(loc, mt.Schema, mt.Examples, opts...)
+		v.schema(loc+".schema", mt.Schema)
+	}
+}
+
+// examples validates the named examples at loc against schema.
+func (v *examplesValidator) examples(loc string, schema *openapi3.SchemaRef, examples openapi3.Examples, opts ...openapi3.SchemaValidationOption) {
+	for _, name := range sortedKeys(examples) {
+		if ex := examples[name]; ex != nil && ex.Value != nil {
+			v.check(loc+".examples."+name, schema, ex.Value.Value, opts...)
+		}
+	}
+}
+
+// sortedKeys returns the sorted keys of m.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,82 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const examplesSpec = `
+openapi: 3.0.4
+info:
+  title: Examples API
+  version: 1.0.0
+paths:
+  /items:
+    get:
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+          example: ten
+      responses:
+        '200':
+          description: OK
+          headers:
+            X-Total:
+              schema:
+                type: integer
+              example: many
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Item'
+              examples:
+                valid:
+                  value:
+                    id: 1
+                invalid:
+                  value:
+                    id: one
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Item'
+            example:
+              name: 1
+      responses:
+        '201':
+          description: Created
+components:
+  schemas:
+    Item:
+      type: object
+      required:
+        - id
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          example: 2
+`
+
+func TestOpenAPIWithConfig_ValidateExamples(t *testing.T) {
+	_, err := NewOpenAPI(Config{SchemaBytes: []byte(examplesSpec), ValidateExamples: true})
+
+	assert.EqualError(t, err, "invalid examples: "+
+		"components.schemas.Item.properties.name.example: value must be a string; "+
+		"paths./items.get.parameters.limit.example: value must be an integer; "+
+		"paths./items.get.responses.200.content.application/json.examples.invalid: id: value must be an integer; "+
+		"paths./items.get.responses.200.headers.X-Total.example: value must be an integer; "+
+		"paths./items.post.requestBody.content.application/json.example: id: property 'id' is missing; "+
+		"paths./items.post.requestBody.content.application/json.example: name: value must be a string")
+}
+
+func TestOpenAPIWithConfig_ValidateExamples_Valid(t *testing.T) {
+	_, err := NewOpenAPI(Config{Schema: "./fixtures/openapi.yaml", ValidateExamples: true})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// ValidateExchange validates a complete HTTP exchange against the
+// specification set in config, like RequestValidator.ValidateExchange. The
+// specification is loaded on every call, so a RequestValidator is to be
+// preferred to validate many exchanges.
+func ValidateExchange(req *http.Request, resp *http.Response, config Config) error {
+	v, err := NewRequestValidator(config)
+	if err != nil {
+		return err
+	}
+
+	return v.ValidateExchange(req, resp)
+}
+
+// ValidateExchange validates a complete HTTP exchange against the
+// specification: it matches the route of req, validates req and then
+// validates resp against the same operation. Undeclared response statuses
+// are rejected. Meant for contract tests. The bodies of req and resp are
+// restored so they can still be read.
+func (v *RequestValidator) ValidateExchange(req *http.Request, resp *http.Response) error {
+	input, err := v.validate(req)
+	if err != nil {
+		return err
+	}
+
+	var b []byte
+	if resp.Body != nil {
+		b, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(b))
+	}
+
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 resp.StatusCode,
+		Header:                 resp.Header,
+		Options: &openapi3filter.Options{
+			IncludeResponseStatus: true,
+			MultiError:            true,
+		},
+	}
+	responseValidationInput.SetBodyBytes(b)
+
+	return validateResponse(req.Context(), responseValidationInput)
+}
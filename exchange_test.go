@@ -0,0 +1,100 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateExchange(t *testing.T) {
+	v := MustNewRequestValidator(Config{Schema: "./fixtures/openapi.yaml"})
+
+	testCases := []struct {
+		name        string
+		reqBody     string
+		status      int
+		contentType string
+		respBody    string
+		err         string
+	}{
+		{
+			"valid",
+			`{"username": "test"}`,
+			http.StatusOK,
+			echo.MIMEApplicationJSON,
+			`{"username": "test"}`,
+			"",
+		},
+		{
+			"invalid request",
+			`{"username": "a"}`,
+			http.StatusOK,
+			echo.MIMEApplicationJSON,
+			`{"username": "test"}`,
+			"failed validating request: username: minimum string length is 2",
+		},
+		{
+			"invalid response",
+			`{"username": "test"}`,
+			http.StatusOK,
+			echo.MIMEApplicationJSON,
+			`{"username": "a"}`,
+			"failed validating response: username: minimum string length is 2",
+		},
+		{
+			"undeclared status",
+			`{"username": "test"}`,
+			http.StatusTeapot,
+			echo.MIMEApplicationJSON,
+			`{}`,
+			"failed validating response: status is not supported",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(tc.reqBody))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+			rec := httptest.NewRecorder()
+			rec.Header().Set(echo.HeaderContentType, tc.contentType)
+			rec.WriteHeader(tc.status)
+			_, _ = rec.WriteString(tc.respBody)
+
+			err := v.ValidateExchange(req, rec.Result())
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestValidateExchange_Config(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(`{"username": "test"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec.WriteHeader(http.StatusOK)
+	_, _ = rec.WriteString(`{"username": "test"}`)
+
+	err := ValidateExchange(req, rec.Result(), Config{Schema: "./fixtures/openapi.yaml"})
+	assert.NoError(t, err)
+}
+
+func TestValidateExchange_Invalid_Schema(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder().Result()
+
+	err := ValidateExchange(req, resp, Config{Schema: "./fixtures/invalid.yaml"})
+	assert.Error(t, err)
+
+	err = ValidateExchange(req, resp, Config{})
+	assert.EqualError(t, err, "either spec, schema, schemaBytes, schemaReader or schemaURL is required")
+}
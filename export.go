@@ -0,0 +1,94 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+	"github.com/labstack/echo/v4"
+)
+
+// ExportSpec returns the effective specification, as loaded and resolved,
+// in format "json" or "yaml". Useful for debugging and client generation.
+func (v *RequestValidator) ExportSpec(format string) ([]byte, error) {
+	return exportSpec(v.spec.schema, format)
+}
+
+// exportSpec marshals doc in format "json" or "yaml".
+func exportSpec(doc *openapi3.T, format string) ([]byte, error) {
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed marshaling schema: %v", err)
+	}
+
+	switch format {
+	case "json":
+		return b, nil
+	case "yaml":
+		b, err = yaml.JSONToYAML(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed converting schema to yaml: %v", err)
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// specFormat returns the format the spec is served in at p, if p is
+// specPath or specPath with its extension replaced by ".yaml" or ".yml".
+func specFormat(specPath string, p string) (string, bool) {
+	base := strings.TrimSuffix(specPath, path.Ext(specPath))
+	switch p {
+	case specPath:
+		if ext := path.Ext(specPath); ext == ".yaml" || ext == ".yml" {
+			return "yaml", true
+		}
+		return "json", true
+	case base + ".yaml", base + ".yml":
+		return "yaml", true
+	}
+	return "", false
+}
+
+// serveSpec responds with doc in format, with the URLs of its servers
+// rewritten to the scheme and host of the request if rewrite is set.
+func serveSpec(c echo.Context, doc *openapi3.T, format string, rewrite bool) error {
+	if rewrite {
+		doc = withRequestServers(doc, c.Scheme()+"://"+c.Request().Host)
+	}
+
+	b, err := exportSpec(doc, format)
+	if err != nil {
+		return err
+	}
+
+	contentType := echo.MIMEApplicationJSON
+	if format == "yaml" {
+		contentType = "application/yaml"
+	}
+	return c.Blob(http.StatusOK, contentType, b)
+}
+
+// withRequestServers returns a copy of doc whose servers have origin as
+// scheme and host, keeping their path, or a single server at origin if it
+// has none.
+func withRequestServers(doc *openapi3.T, origin string) *openapi3.T {
+	res := *doc
+	res.Servers = openapi3.Servers{{URL: origin}}
+	if len(doc.Servers) == 0 {
+		return &res
+	}
+
+	res.Servers = make(openapi3.Servers, 0, len(doc.Servers))
+	for _, server := range serversWithoutHosts(doc.Servers) {
+		s := *server
+		s.URL = origin + strings.TrimSuffix(server.URL, "/")
+		res.Servers = append(res.Servers, &s)
+	}
+	return &res
+}
@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestValidator_ExportSpec(t *testing.T) {
+	v := MustNewRequestValidator(Config{Schema: "./fixtures/openapi.yaml"})
+
+	for _, format := range []string{"json", "yaml"} {
+		t.Run(format, func(t *testing.T) {
+			b, err := v.ExportSpec(format)
+			assert.NoError(t, err)
+
+			doc, err := openapi3.NewLoader().LoadFromData(b)
+			assert.NoError(t, err)
+			assert.NoError(t, doc.Validate(context.Background()))
+
+			assert.Equal(t, v.spec.schema.Info.Title, doc.Info.Title)
+			assert.Equal(t, v.spec.schema.Paths.Len(), doc.Paths.Len())
+			assert.NotNil(t, doc.Paths.Find("/validation").Post)
+
+			// const is exported as the enum it's enforced as
+			schema := doc.Paths.Find("/subscriptions").Post.RequestBody.Value.Content.Get("application/json").Schema.Value
+			assert.Equal(t, []any{"subscription"}, schema.Properties["type"].Value.Enum)
+		})
+	}
+}
+
+func TestRequestValidator_ExportSpec_Unsupported_Format(t *testing.T) {
+	v := MustNewRequestValidator(Config{Schema: "./fixtures/openapi.yaml"})
+
+	_, err := v.ExportSpec("xml")
+	assert.EqualError(t, err, `unsupported format "xml"`)
+}
+
+func TestOpenAPIWithConfig_SpecPath(t *testing.T) {
+	testCases := []struct {
+		name        string
+		specPath    string
+		target      string
+		rewrite     bool
+		statusCode  int
+		contentType string
+		servers     []string
+	}{
+		{"json", "/openapi.json", "/openapi.json", false, http.StatusOK, echo.MIMEApplicationJSON, []string{"/api"}},
+		{"yaml", "/openapi.json", "/openapi.yaml", false, http.StatusOK, "application/yaml", []string{"/api"}},
+		{"yml", "/openapi.json", "/openapi.yml", false, http.StatusOK, "application/yaml", []string{"/api"}},
+		{"yaml spec path", "/spec.yaml", "/spec.yaml", false, http.StatusOK, "application/yaml", []string{"/api"}},
+		{"rewritten servers", "/openapi.json", "/openapi.json", true, http.StatusOK, echo.MIMEApplicationJSON, []string{"http://example.com/api"}},
+		{"other path", "/openapi.json", "/openapi.txt", false, http.StatusNotFound, "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:                 "./fixtures/servers.yaml",
+				SpecPath:               tc.specPath,
+				SpecServersFromRequest: tc.rewrite,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Host = "example.com"
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.statusCode != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.contentType, resp.Header().Get(echo.HeaderContentType))
+
+			doc, err := openapi3.NewLoader().LoadFromData(resp.Body.Bytes())
+			assert.NoError(t, err)
+
+			var servers []string
+			for _, server := range doc.Servers {
+				servers = append(servers, server.URL)
+			}
+			assert.Equal(t, tc.servers, servers)
+		})
+	}
+}
+
+func TestWithRequestServers(t *testing.T) {
+	doc := &openapi3.T{Servers: openapi3.Servers{{URL: "https://api.example.com/v1"}, {URL: "https://api.example.com"}}}
+
+	res := withRequestServers(doc, "http://localhost:1323")
+
+	assert.Equal(t, "http://localhost:1323/v1", res.Servers[0].URL)
+	assert.Equal(t, "http://localhost:1323", res.Servers[1].URL)
+	assert.Equal(t, "https://api.example.com/v1", doc.Servers[0].URL)
+
+	res = withRequestServers(&openapi3.T{}, "http://localhost:1323")
+	assert.Equal(t, "http://localhost:1323", res.Servers[0].URL)
+}
@@ -0,0 +1,181 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+var registerFormBodyDecoderOnce sync.Once
+
+// registerFormBodyDecoder replaces the application/x-www-form-urlencoded
+// body decoder of kin-openapi with decodeFormBody.
+func registerFormBodyDecoder() {
+	registerFormBodyDecoderOnce.Do(func() {
+		openapi3filter.RegisterBodyDecoder("application/x-www-form-urlencoded", decodeFormBody)
+	})
+}
+
+// decodeFormBody decodes an application/x-www-form-urlencoded body into an
+// object of schema. Only the fields present are decoded, so that missing
+// ones are reported as such, and values that can't be converted to the type
+// of their property are left as strings for validation to report.
+//
+// Array fields are decoded according to the style of their encoding: form,
+// exploded as repeated fields or not as comma separated values,
+// spaceDelimited or pipeDelimited. Object fields are decoded from name[key]
+// fields with the deepObject style, from key,value pairs with the form style
+// not exploded and from fields named after their properties otherwise.
+// Fields encoded as application/json are decoded as JSON. Fields not
+// declared are kept as strings, so additionalProperties apply.
+func decodeFormBody(body io.Reader, _ http.Header, schema *openapi3.SchemaRef, encFn openapi3filter.EncodingFn) (any, error) {
+	if schema == nil || schema.Value == nil || schema.Value.Type != openapi3.TypeObject {
+		return nil, fmt.Errorf("unsupported request body schema, object expected")
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := url.ParseQuery(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	object := make(map[string]any)
+	consumed := make(map[string]bool)
+	for name, ref := range schema.Value.Properties {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+
+		var encoding *openapi3.Encoding
+		if encFn != nil {
+			encoding = encFn(name)
+		}
+
+		if v, ok := decodeFormField(values, name, ref.Value, encoding, consumed); ok {
+			object[name] = v
+		}
+	}
+
+	for name, v := range values {
+		if consumed[name] {
+			continue
+		}
+		if len(v) == 1 {
+			object[name] = v[0]
+			continue
+		}
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, item)
+		}
+		object[name] = items
+	}
+
+	return object, nil
+}
+
+// decodeFormField decodes the field name of schema from values according to
+// encoding, marking the fields it's decoded from as consumed.
+func decodeFormField(values url.Values, name string, schema *openapi3.Schema, encoding *openapi3.Encoding, consumed map[string]bool) (any, bool) {
+	style := openapi3.SerializationForm
+	explode := true
+	if encoding != nil {
+		if encoding.Style != "" {
+			style = encoding.Style
+		}
+		if encoding.Explode != nil {
+			explode = *encoding.Explode
+		}
+
+		if mediaType, _, err := mime.ParseMediaType(encoding.ContentType); err == nil && mediaType == ApplicationJSON {
+			v, ok := values[name]
+			if !ok {
+				return nil, false
+			}
+			consumed[name] = true
+
+			var decoded any
+			if err := json.Unmarshal([]byte(firstValue(v)), &decoded); err != nil {
+				return firstValue(v), true
+			}
+			return decoded, true
+		}
+	}
+
+	if schema.Type == openapi3.TypeObject {
+		var get func(key string) (string, bool)
+		switch {
+		case style == openapi3.SerializationDeepObject:
+			get = func(key string) (string, bool) {
+				field := name + "[" + key + "]"
+				v, ok := values[field]
+				consumed[field] = consumed[field] || ok
+				return firstValue(v), ok
+			}
+		case style == openapi3.SerializationForm && !explode:
+			v, ok := values[name]
+			if !ok {
+				return nil, false
+			}
+			consumed[name] = true
+
+			pairs := make(map[string]string)
+			parts := strings.Split(firstValue(v), ",")
+			for i := 0; i+1 < len(parts); i += 2 {
+				pairs[parts[i]] = parts[i+1]
+			}
+			get = func(key string) (string, bool) {
+				v, ok := pairs[key]
+				return v, ok
+			}
+		default:
+			get = func(key string) (string, bool) {
+				v, ok := values[key]
+				consumed[key] = consumed[key] || ok
+				return firstValue(v), ok
+			}
+		}
+
+		v, ok := decodeObjectProperties(schema, get)
+		if !ok {
+			return nil, false
+		}
+		return v, true
+	}
+
+	v, ok := values[name]
+	if !ok {
+		return nil, false
+	}
+	consumed[name] = true
+
+	if schema.Type != openapi3.TypeArray {
+		return decodePrimitive(schema, firstValue(v)), true
+	}
+
+	if style == openapi3.SerializationForm && explode {
+		return decodeArray(schema, v), true
+	}
+
+	sep := ","
+	switch style {
+	case openapi3.SerializationSpaceDelimited:
+		sep = " "
+	case openapi3.SerializationPipeDelimited:
+		sep = "|"
+	}
+
+	return decodeArray(schema, strings.Split(firstValue(v), sep)), true
+}
@@ -0,0 +1,54 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_DecodeFormBodies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		message    string
+	}{
+		{"valid", "name=test&age=30&tags=a&tags=b&ids=1|2&address[city]=Montreal&address[zip]=123&meta=%7B%22source%22%3A%22web%22%7D", http.StatusOK, ""},
+		{"optional fields missing", "name=test", http.StatusOK, ""},
+		{"required field missing", "age=30", http.StatusUnprocessableEntity, "name"},
+		{"invalid integer", "name=test&age=x", http.StatusUnprocessableEntity, "age"},
+		{"invalid array item", "name=test&ids=1|x", http.StatusUnprocessableEntity, "ids"},
+		{"too many items", "name=test&tags=a&tags=b&tags=c", http.StatusUnprocessableEntity, "tags"},
+		{"invalid object property", "name=test&address[zip]=123", http.StatusUnprocessableEntity, "city"},
+		{"invalid json field", "name=test&meta=%7B%22source%22%3A1%7D", http.StatusUnprocessableEntity, "source"},
+		{"undeclared field", "name=test&extra=1", http.StatusUnprocessableEntity, "extra"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/form", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:           "./fixtures/openapi.yaml",
+				DecodeFormBodies: true,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/form", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			assert.Contains(t, resp.Body.String(), tc.message)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"errors"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// hostnameRegexp matches RFC 1123 hostnames.
+var hostnameRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)(\.[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$`)
+
+var defineFormatsOnce sync.Once
+
+// defineFormats registers the email, uri, hostname, ipv4 and ipv6 string
+// formats. kin-openapi keeps formats in a global registry, so they apply to
+// every schema of the process once registered.
+func defineFormats() {
+	defineFormatsOnce.Do(func() {
+		openapi3.DefineStringFormatCallback("email", validateEmail)
+		openapi3.DefineStringFormatCallback("uri", validateURI)
+		openapi3.DefineStringFormatCallback("hostname", validateHostname)
+		openapi3.DefineIPv4Format()
+		openapi3.DefineIPv6Format()
+	})
+}
+
+func validateEmail(s string) error {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return errors.New("invalid email")
+	}
+	return nil
+}
+
+func validateURI(s string) error {
+	u, err := url.Parse(s)
+	if err != nil || !u.IsAbs() {
+		return errors.New("invalid uri")
+	}
+	return nil
+}
+
+func validateHostname(s string) error {
+	if len(s) > 253 || !hostnameRegexp.MatchString(s) {
+		return errors.New("invalid hostname")
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_ValidateFormats(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid email", `{"email": "user@example.com"}`, http.StatusOK, nil},
+		{"invalid email", `{"email": "user"}`, http.StatusUnprocessableEntity, []string{"email: value is not a valid email"}},
+		{"valid uri", `{"website": "https://example.com/path?q=1"}`, http.StatusOK, nil},
+		{"invalid uri", `{"website": "example.com"}`, http.StatusUnprocessableEntity, []string{"website: value is not a valid uri"}},
+		{"valid hostname", `{"host": "api.example.com"}`, http.StatusOK, nil},
+		{"invalid hostname", `{"host": "-example.com"}`, http.StatusUnprocessableEntity, []string{"host: value is not a valid hostname"}},
+		{"valid ipv4", `{"ipv4": "10.0.0.1"}`, http.StatusOK, nil},
+		{"invalid ipv4", `{"ipv4": "::1"}`, http.StatusUnprocessableEntity, []string{"ipv4: value is not a valid ipv4"}},
+		{"valid ipv6", `{"ipv6": "2001:db8::1"}`, http.StatusOK, nil},
+		{"invalid ipv6", `{"ipv6": "10.0.0.1"}`, http.StatusUnprocessableEntity, []string{"ipv6: value is not a valid ipv6"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/contacts", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:          "./fixtures/openapi.yaml",
+				ValidateFormats: true,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/contacts", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Formats(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"phone": "+15551234567"}`, http.StatusOK, nil},
+		{"invalid", `{"phone": "555-1234"}`, http.StatusUnprocessableEntity, []string{"phone: value is not a valid e164"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/contacts", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				Formats: map[string]func(string) error{
+					"e164": func(value string) error {
+						if !regexp.MustCompile(`^\+[1-9]\d{1,14}$`).MatchString(value) {
+							return errors.New("invalid e164 phone number")
+						}
+						return nil
+					},
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/contacts", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
@@ -3,13 +3,15 @@ package openapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
-	"sort"
 	"strings"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
 	"github.com/labstack/echo/v4"
 )
 
@@ -43,9 +45,49 @@ type HandlerConfig struct {
 	// IncludeResponseStatus so ValidateResponse fails on response
 	// statuses not defined in the OpenAPI spec.
 	// Optional. Defaults to true.
+	//
+	// Deprecated: use ResponseStatusMode, which takes precedence when set.
 	IncludeResponseStatus bool
+
+	// ResponseStatusMode defines how Validate handles response statuses
+	// not defined in the OpenAPI spec: ResponseStatusStrict fails,
+	// ResponseStatusWarn logs a warning and responds and ResponseStatusOff
+	// responds.
+	// Optional. Defaults to ResponseStatusStrict if IncludeResponseStatus
+	// is true, ResponseStatusOff otherwise.
+	ResponseStatusMode ResponseStatusMode
+
+	// RequireDeclaredBody makes Validate fail on empty responses, or nil
+	// values, for statuses whose response declares content in the spec.
+	// Optional. Defaults to false.
+	RequireDeclaredBody bool
+
+	// ExcludeResponseBodyContentTypes makes Validate skip response body
+	// validation for these content types, e.g. "text/csv". The status and
+	// content type are still validated against the spec.
+	// Optional.
+	ExcludeResponseBodyContentTypes []string
+
+	// WriteOnlyProperties defines how writeOnly properties in responses are
+	// handled: PropertyReject fails validation, PropertyStrip removes them
+	// from JSON responses before validation and PropertyIgnore allows them.
+	// Optional. Defaults to PropertyReject.
+	WriteOnlyProperties PropertyMode
 }
 
+// ResponseStatusMode defines how response statuses not defined in the
+// OpenAPI spec are handled.
+type ResponseStatusMode int
+
+const (
+	// ResponseStatusStrict fails validation of undefined statuses.
+	ResponseStatusStrict ResponseStatusMode = iota + 1
+	// ResponseStatusWarn logs a warning for undefined statuses.
+	ResponseStatusWarn
+	// ResponseStatusOff allows undefined statuses.
+	ResponseStatusOff
+)
+
 var DefaultHandlerConfig = HandlerConfig{
 	ContentType:           ApplicationJSON,
 	ValidatorKey:          "validator",
@@ -68,6 +110,13 @@ func NewHandlerWithConfig(config HandlerConfig) *Handler {
 		config.ValidatorKey = DefaultHandlerConfig.ValidatorKey
 	}
 
+	if config.ResponseStatusMode == 0 {
+		config.ResponseStatusMode = ResponseStatusOff
+		if config.IncludeResponseStatus {
+			config.ResponseStatusMode = ResponseStatusStrict
+		}
+	}
+
 	return &Handler{Config: config}
 }
 
@@ -79,6 +128,32 @@ func (h *Handler) ValidateWithContentType(c echo.Context, code int, contentType
 	return h.validate(c, code, contentType, v)
 }
 
+// RespondList wraps items and meta in a {"data": items, "meta": meta}
+// envelope, validates it against the components schema named envelope, if
+// any, and then responds like Validate.
+func (h *Handler) RespondList(c echo.Context, code int, items any, meta any, envelope string) error {
+	body := echo.Map{"data": items, "meta": meta}
+
+	if envelope != "" {
+		input, ok := c.Get(h.Config.ValidatorKey).(*openapi3filter.RequestValidationInput)
+		if !ok {
+			return fmt.Errorf("validator key is wrong type")
+		}
+
+		schema, err := componentSchema(input.Route.Spec, envelope)
+		if err != nil {
+			return err
+		}
+
+		err = validateValue(schema, body)
+		if err != nil {
+			return fmt.Errorf("failed validating response: %v", err)
+		}
+	}
+
+	return h.Validate(c, code, body)
+}
+
 func (h *Handler) validate(c echo.Context, code int, contentType string, v any) error {
 	// there's nothing to validate so just return
 	if code == http.StatusNoContent {
@@ -101,7 +176,7 @@ func (h *Handler) validate(c echo.Context, code int, contentType string, v any)
 		c.Response().Header().Add("Content-Type", contentType)
 		b, err = json.Marshal(v)
 	} else {
-		c.Response().Header().Add("Content-Type", echo.MIMETextPlain)
+		c.Response().Header().Add("Content-Type", contentType)
 		switch t := v.(type) {
 		case string:
 			b = []byte(v.(string))
@@ -116,47 +191,179 @@ func (h *Handler) validate(c echo.Context, code int, contentType string, v any)
 		return fmt.Errorf("failed marshaling response: %v", err)
 	}
 
+	if h.Config.WriteOnlyProperties == PropertyStrip && strings.HasPrefix(contentType, ApplicationJSON) {
+		if schema := responseBodySchema(input.Route, code, contentType); schema != nil {
+			b, err = stripProperties(schema, b, func(s *openapi3.Schema) bool { return s.WriteOnly })
+			if err != nil {
+				return fmt.Errorf("failed stripping response: %v", err)
+			}
+		}
+	}
+
+	if _, skip := skipValidation(input.Route.Operation); skip {
+		return c.Blob(code, contentType, b)
+	}
+
+	if h.Config.RequireDeclaredBody && (v == nil || len(b) == 0) {
+		if response := declaredResponse(input.Route, code); response != nil && len(response.Content) > 0 {
+			return fmt.Errorf("failed validating response: body is required for status %d but missing", code)
+		}
+	}
+
+	excludeBody := h.Config.ExcludeResponseBody
+	if containsMediaType(h.Config.ExcludeResponseBodyContentTypes, contentType) {
+		excludeBody = true
+
+		err = validateResponseContentType(input.Route, code, contentType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if h.Config.ResponseStatusMode == ResponseStatusWarn && !isResponseStatusDefined(input.Route, code) {
+		c.Logger().Warnf("response status %d is not defined for %s %s", code, input.Route.Method, input.Route.Path)
+	}
+
 	responseValidationInput := &openapi3filter.ResponseValidationInput{
 		RequestValidationInput: input,
 		Status:                 c.Response().Status,
 		Header:                 c.Response().Header(),
 		Options: &openapi3filter.Options{
-			ExcludeRequestBody:    h.Config.ExcludeRequestBody,
-			ExcludeResponseBody:   h.Config.ExcludeResponseBody,
-			IncludeResponseStatus: h.Config.IncludeResponseStatus,
-			MultiError:            true,
+			ExcludeRequestBody:          h.Config.ExcludeRequestBody,
+			ExcludeResponseBody:         excludeBody,
+			IncludeResponseStatus:       h.Config.ResponseStatusMode == ResponseStatusStrict,
+			ExcludeWriteOnlyValidations: h.Config.WriteOnlyProperties != PropertyReject,
+			MultiError:                  true,
 		},
 	}
 	responseValidationInput.SetBodyBytes(b)
 
-	ctx := context.Background()
-	err = openapi3filter.ValidateResponse(ctx, responseValidationInput)
+	err = validateResponse(context.Background(), responseValidationInput)
 	if err != nil {
-		switch err := err.(type) {
-		case nil:
-		case *openapi3filter.ResponseError:
-			if me, ok := err.Err.(openapi3.MultiError); ok {
-				issues := convertError(me)
-				names := make([]string, 0, len(issues))
-
-				for k := range issues {
-					names = append(names, k)
-				}
-				sort.Strings(names)
-				var errors []string
-				for _, k := range names {
-					msgs := issues[k]
-					for _, msg := range msgs {
-						errors = append(errors, msg)
-					}
-				}
-
-				return fmt.Errorf("failed validating response: %s", strings.Join(errors, "; "))
-			}
-		default:
-			return fmt.Errorf("failed validating response: %v", err)
+		return err
+	}
+
+	return c.Blob(code, contentType, b)
+}
+
+// containsMediaType reports whether the media type of contentType, ignoring
+// parameters, is in list.
+func containsMediaType(list []string, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	for _, v := range list {
+		if mt, _, err := mime.ParseMediaType(v); err == nil && mt == mediaType {
+			return true
 		}
 	}
+	return false
+}
+
+// isResponseStatusDefined reports whether route defines a response for
+// status, or a default response.
+func isResponseStatusDefined(route *routers.Route, status int) bool {
+	return declaredResponse(route, status) != nil
+}
+
+// declaredResponse returns the response route declares for status, falling
+// back to the default response, or nil.
+func declaredResponse(route *routers.Route, status int) *openapi3.Response {
+	responses := route.Operation.Responses
+	if responses == nil {
+		return nil
+	}
+
+	responseRef := responses.Status(status)
+	if responseRef == nil {
+		responseRef = responses.Default()
+	}
+	if responseRef == nil {
+		return nil
+	}
+
+	return responseRef.Value
+}
+
+// responseBodySchema returns the schema route declares for the response body
+// of status with contentType, or nil.
+func responseBodySchema(route *routers.Route, status int, contentType string) *openapi3.Schema {
+	response := declaredResponse(route, status)
+	if response == nil {
+		return nil
+	}
+
+	mediaType := response.Content.Get(contentType)
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+
+	return mediaType.Schema.Value
+}
+
+// validateResponseContentType validates that the response of route for
+// status declares contentType, when it declares any content. Used when body
+// validation is skipped, as openapi3filter then skips this check too.
+func validateResponseContentType(route *routers.Route, status int, contentType string) error {
+	response := declaredResponse(route, status)
+	if response == nil || len(response.Content) == 0 {
+		return nil
+	}
+
+	if response.Content.Get(contentType) == nil {
+		return fmt.Errorf("failed validating response: response header Content-Type has unexpected value: %q", contentType)
+	}
+
+	return nil
+}
+
+// validateResponse validates input against the spec and flattens any schema
+// issues into a single error.
+func validateResponse(ctx context.Context, input *openapi3filter.ResponseValidationInput) error {
+	err := openapi3filter.ValidateResponse(ctx, input)
+	switch err := err.(type) {
+	case nil:
+	case *openapi3filter.ResponseError:
+		if me := asMultiError(err.Err); me != nil {
+			issues := convertError(me, CaseAsIs)
+			return fmt.Errorf("failed validating response: %s", strings.Join(flattenIssues(issues), "; "))
+		}
+		return fmt.Errorf("failed validating response: %v", err)
+	default:
+		return fmt.Errorf("failed validating response: %v", err)
+	}
+
+	return nil
+}
+
+// componentSchema returns the schema named name in the components of doc.
+func componentSchema(doc *openapi3.T, name string) (*openapi3.Schema, error) {
+	if doc.Components != nil {
+		if ref, ok := doc.Components.Schemas[name]; ok && ref.Value != nil {
+			return ref.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("schema %s not found", name)
+}
+
+// validateValue validates the JSON representation of v against schema.
+func validateValue(schema *openapi3.Schema, v any) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var value any
+	if err = json.Unmarshal(b, &value); err != nil {
+		return err
+	}
+
+	err = schema.VisitJSON(value, openapi3.MultiErrors())
+	if me := asMultiError(err); me != nil {
+		return errors.New(strings.Join(flattenIssues(convertError(me, CaseAsIs)), "; "))
+	}
 
-	return c.Blob(code, h.Config.ContentType, b)
+	return err
 }
@@ -1,11 +1,14 @@
 package openapi
 
 import (
+	"bytes"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -140,3 +143,242 @@ func TestHandler_ValidateWithContentType_Text_Plain(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_RespondList(t *testing.T) {
+	testCases := []struct {
+		name       string
+		items      any
+		meta       any
+		envelope   string
+		statusCode int
+	}{
+		{"valid", []echo.Map{{"id": 1}, {"id": 2}}, echo.Map{"total": 2}, "OrderList", http.StatusOK},
+		{"valid without envelope", []echo.Map{{"id": 1}}, echo.Map{"total": 1}, "", http.StatusOK},
+		{"invalid items", []echo.Map{{"id": "a"}}, echo.Map{"total": 1}, "OrderList", http.StatusInternalServerError},
+		{"invalid meta", []echo.Map{}, echo.Map{}, "OrderList", http.StatusInternalServerError},
+		{"unknown envelope", []echo.Map{}, echo.Map{"total": 0}, "Unknown", http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := TestHandler{NewHandler()}
+
+			e.GET("/orders", func(c echo.Context) error {
+				return h.RespondList(c, http.StatusOK, tc.items, tc.meta, tc.envelope)
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestHandler_ExcludeResponseBodyContentTypes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        any
+		statusCode  int
+	}{
+		{"csv skips body validation", "text/csv", "id,name\n1,test\n", http.StatusOK},
+		{"json is validated", echo.MIMEApplicationJSON, echo.Map{"count": "one"}, http.StatusInternalServerError},
+		{"valid json", echo.MIMEApplicationJSON, echo.Map{"count": 1}, http.StatusOK},
+		{"undeclared content type", "text/tab-separated-values", "id\tname\n", http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := TestHandler{NewHandlerWithConfig(HandlerConfig{
+				ExcludeResponseBodyContentTypes: []string{"text/csv", "text/tab-separated-values"},
+			})}
+
+			e.GET("/reports", func(c echo.Context) error {
+				return h.ValidateWithContentType(c, http.StatusOK, tc.contentType, tc.body)
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/reports", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.statusCode == http.StatusOK {
+				assert.Equal(t, tc.contentType, resp.Header().Get(echo.HeaderContentType))
+			}
+		})
+	}
+}
+
+func TestHandler_Validate_WriteOnly(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       echo.Map
+		statusCode int
+	}{
+		{"without write only", echo.Map{"id": "1", "username": "test"}, http.StatusOK},
+		{"with write only", echo.Map{"id": "1", "username": "test", "password": "secret"}, http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := TestHandler{NewHandler()}
+
+			var err error
+			e.GET("/me", func(c echo.Context) error {
+				err = h.Validate(c, http.StatusOK, tc.body)
+				return err
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.statusCode != http.StatusOK {
+				assert.EqualError(t, err, `failed validating response: writeOnly property "password" in response`)
+			}
+		})
+	}
+}
+
+func TestHandler_WriteOnlyProperties(t *testing.T) {
+	testCases := []struct {
+		name       string
+		mode       PropertyMode
+		statusCode int
+		body       string
+	}{
+		{"reject", PropertyReject, http.StatusInternalServerError, ""},
+		{"strip", PropertyStrip, http.StatusOK, `{"id":"1","username":"test"}`},
+		{"ignore", PropertyIgnore, http.StatusOK, `{"id":"1","password":"secret","username":"test"}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := TestHandler{NewHandlerWithConfig(HandlerConfig{WriteOnlyProperties: tc.mode})}
+
+			e.GET("/me", func(c echo.Context) error {
+				return h.Validate(c, http.StatusOK, echo.Map{"id": "1", "username": "test", "password": "secret"})
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandler_ResponseStatusMode(t *testing.T) {
+	testCases := []struct {
+		name       string
+		config     HandlerConfig
+		statusCode int
+		warning    bool
+	}{
+		{"strict", HandlerConfig{ResponseStatusMode: ResponseStatusStrict}, http.StatusInternalServerError, false},
+		{"warn", HandlerConfig{ResponseStatusMode: ResponseStatusWarn}, http.StatusTeapot, true},
+		{"off", HandlerConfig{ResponseStatusMode: ResponseStatusOff}, http.StatusTeapot, false},
+		{"include response status", HandlerConfig{IncludeResponseStatus: true}, http.StatusInternalServerError, false},
+		{"exclude response status", HandlerConfig{IncludeResponseStatus: false}, http.StatusTeapot, false},
+		{"mode takes precedence", HandlerConfig{IncludeResponseStatus: true, ResponseStatusMode: ResponseStatusOff}, http.StatusTeapot, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			buf := &bytes.Buffer{}
+			e.Logger.SetOutput(buf)
+			e.Logger.SetLevel(log.WARN)
+
+			h := TestHandler{NewHandlerWithConfig(tc.config)}
+
+			e.GET("/", func(c echo.Context) error {
+				return h.Validate(c, http.StatusTeapot, echo.Map{"message": "welcome"})
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.warning, strings.Contains(buf.String(), "response status 418 is not defined for GET /"))
+		})
+	}
+}
+
+func TestHandler_RequireDeclaredBody(t *testing.T) {
+	testCases := []struct {
+		name       string
+		require    bool
+		path       string
+		body       any
+		statusCode int
+	}{
+		{"empty declared body", true, "/text", "", http.StatusInternalServerError},
+		{"nil declared body", true, "/", nil, http.StatusInternalServerError},
+		{"declared body", true, "/text", "ok", http.StatusOK},
+		{"empty undeclared body", true, "/error", "", http.StatusOK},
+		{"empty declared body not required", false, "/text", "", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := TestHandler{NewHandlerWithConfig(HandlerConfig{RequireDeclaredBody: tc.require})}
+
+			var err error
+			e.GET(tc.path, func(c echo.Context) error {
+				if tc.body == nil {
+					err = h.Validate(c, http.StatusOK, tc.body)
+				} else {
+					err = h.ValidateWithContentType(c, http.StatusOK, echo.MIMETextPlain, tc.body)
+				}
+				return err
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.statusCode != http.StatusOK {
+				assert.EqualError(t, err, "failed validating response: body is required for status 200 but missing")
+			}
+		})
+	}
+}
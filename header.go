@@ -0,0 +1,111 @@
+package openapi
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// withoutAbsentHeaderProperties returns me without the errors of optional
+// properties absent from object header parameters of req. openapi3filter
+// decodes these properties as null, failing validation of non-nullable
+// properties.
+func withoutAbsentHeaderProperties(me openapi3.MultiError, header http.Header) openapi3.MultiError {
+	var res openapi3.MultiError
+	for _, err := range me {
+		re, ok := err.(*openapi3filter.RequestError)
+		if !ok || re.Parameter == nil || re.Parameter.In != openapi3.ParameterInHeader ||
+			re.Parameter.Schema == nil || re.Parameter.Schema.Value == nil || re.Parameter.Schema.Value.Type != openapi3.TypeObject {
+			res = append(res, err)
+			continue
+		}
+
+		inner := asMultiError(re.Err)
+		if inner == nil {
+			res = append(res, err)
+			continue
+		}
+
+		schema := re.Parameter.Schema.Value
+		props := headerObjectProperties(header.Get(re.Parameter.Name), re.Parameter.Explode != nil && *re.Parameter.Explode)
+
+		var kept openapi3.MultiError
+		for _, e := range inner {
+			if se, ok := e.(*openapi3.SchemaError); ok && se.SchemaField == "nullable" {
+				if path := se.JSONPointer(); len(path) == 1 && !props[path[0]] && !slices.Contains(schema.Required, path[0]) {
+					continue
+				}
+			}
+			kept = append(kept, e)
+		}
+
+		if len(kept) == 0 {
+			continue
+		}
+
+		filtered := *re
+		filtered.Err = kept
+		res = append(res, &filtered)
+	}
+	return res
+}
+
+// headerObjectProperties returns the names of the properties of the object
+// header value v, serialized with the simple style.
+func headerObjectProperties(v string, explode bool) map[string]bool {
+	props := make(map[string]bool)
+	pairs := strings.Split(v, ",")
+
+	if explode {
+		for _, pair := range pairs {
+			name, _, _ := strings.Cut(pair, "=")
+			props[name] = true
+		}
+		return props
+	}
+
+	for i := 0; i < len(pairs); i += 2 {
+		props[pairs[i]] = true
+	}
+	return props
+}
+
+// withoutIgnoredHeaders returns me without the errors of header parameters
+// named in ignored, compared case-insensitively.
+func withoutIgnoredHeaders(me openapi3.MultiError, ignored []string) openapi3.MultiError {
+	var res openapi3.MultiError
+	for _, err := range me {
+		if re, ok := err.(*openapi3filter.RequestError); ok && re.Parameter != nil && re.Parameter.In == openapi3.ParameterInHeader {
+			if slices.ContainsFunc(ignored, func(name string) bool { return strings.EqualFold(name, re.Parameter.Name) }) {
+				continue
+			}
+		}
+		res = append(res, err)
+	}
+	return res
+}
+
+// generateMissingHeaders sets the required header parameters of route
+// missing from req using generators, keyed by header name.
+func generateMissingHeaders(req *http.Request, route *routers.Route, generators map[string]func() string) {
+	params := append(openapi3.Parameters(nil), route.PathItem.Parameters...)
+	params = append(params, route.Operation.Parameters...)
+
+	for _, ref := range params {
+		param := ref.Value
+		if param == nil || param.In != openapi3.ParameterInHeader || !param.Required || req.Header.Get(param.Name) != "" {
+			continue
+		}
+
+		for name, generate := range generators {
+			if strings.EqualFold(name, param.Name) {
+				req.Header.Set(param.Name, generate())
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_Header_Array(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ids        string
+		filter     string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", "1,2,3", "", http.StatusOK, nil},
+		{"single", "1", "", http.StatusOK, nil},
+		{"valid object", "1", "status=open,limit=10", http.StatusOK, nil},
+		{"partial object", "1", "status=open", http.StatusOK, nil},
+		{"invalid item", "1,a,3", "", http.StatusUnprocessableEntity, []string{"parameter 'X-Ids' in header has an error: 1: value 'a' is an invalid integer"}},
+		{"too many items", "1,2,3,4", "", http.StatusUnprocessableEntity, []string{"parameter 'X-Ids' in header has an error: maximum number of items is 3"}},
+		{"invalid object", "1", "status=pending", http.StatusUnprocessableEntity, []string{"parameter 'X-Filter' in header has an error: status: value is not one of the allowed values ['open','closed']"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/batch", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/batch", nil)
+			req.Header.Set("X-Ids", tc.ids)
+			if tc.filter != "" {
+				req.Header.Set("X-Filter", tc.filter)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestHeaderObjectProperties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		explode  bool
+		expected map[string]bool
+	}{
+		{"explode", "status=open,limit=10", true, map[string]bool{"status": true, "limit": true}},
+		{"no explode", "status,open,limit,10", false, map[string]bool{"status": true, "limit": true}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, headerObjectProperties(tc.value, tc.explode))
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_IgnoredHeaders(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ignored    []string
+		headers    map[string]string
+		statusCode int
+		errors     []string
+	}{
+		{
+			"valid",
+			nil,
+			map[string]string{"X-Forwarded-For": "10.0.0.1", "X-Request-Id": "0b5c7f4e-3a0e-4a3b-9a6e-2f1d3c4b5a69"},
+			http.StatusOK,
+			nil,
+		},
+		{
+			"infrastructure header",
+			nil,
+			map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2", "X-Request-Id": "0b5c7f4e-3a0e-4a3b-9a6e-2f1d3c4b5a69"},
+			http.StatusUnprocessableEntity,
+			[]string{`parameter 'X-Forwarded-For' in header has an error: string doesn't match the regular expression '^(\d{1,3}\.){3}\d{1,3}$'`},
+		},
+		{
+			"ignored infrastructure header",
+			[]string{"x-forwarded-for"},
+			map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2", "X-Request-Id": "0b5c7f4e-3a0e-4a3b-9a6e-2f1d3c4b5a69"},
+			http.StatusOK,
+			nil,
+		},
+		{
+			"missing ignored header",
+			[]string{"X-Forwarded-For"},
+			map[string]string{"X-Request-Id": "0b5c7f4e-3a0e-4a3b-9a6e-2f1d3c4b5a69"},
+			http.StatusOK,
+			nil,
+		},
+		{
+			"other headers still validated",
+			[]string{"X-Forwarded-For"},
+			map[string]string{"X-Forwarded-For": "10.0.0.1, 10.0.0.2", "X-Request-Id": "abc"},
+			http.StatusUnprocessableEntity,
+			nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/internal", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:         "./fixtures/openapi.yaml",
+				IgnoredHeaders: tc.ignored,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/internal", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_AutoGenerateHeaders(t *testing.T) {
+	testCases := []struct {
+		name       string
+		generators map[string]func() string
+		header     string
+		statusCode int
+		expected   string
+	}{
+		{"generated", map[string]func() string{"x-correlation-id": func() string { return "generated-id" }}, "", http.StatusOK, "generated-id"},
+		{"present", map[string]func() string{"X-Correlation-ID": func() string { return "generated-id" }}, "client-id", http.StatusOK, "client-id"},
+		{"invalid generated", map[string]func() string{"X-Correlation-ID": func() string { return "id" }}, "", http.StatusUnprocessableEntity, ""},
+		{"no generator", nil, "", http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var header string
+			e.GET("/correlated", func(c echo.Context) error {
+				header = c.Request().Header.Get("X-Correlation-ID")
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:              "./fixtures/openapi.yaml",
+				AutoGenerateHeaders: tc.generators,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/correlated", nil)
+			if tc.header != "" {
+				req.Header.Set("X-Correlation-ID", tc.header)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.expected, header)
+		})
+	}
+}
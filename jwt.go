@@ -0,0 +1,272 @@
+package openapi
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+)
+
+// JWTConfig defines the config for verifying the JWT bearer tokens of
+// requests to operations requiring an http bearer security scheme.
+type JWTConfig struct {
+	// KeyFunc returns the key verifying tokens signed with alg, using the
+	// key ID kid, if any: a []byte for HS256, HS384 and HS512, an
+	// *rsa.PublicKey for RS256, RS384 and RS512, an *ecdsa.PublicKey for
+	// ES256, ES384 and ES512 or an ed25519.PublicKey for EdDSA.
+	// Required.
+	KeyFunc func(alg string, kid string) (any, error)
+
+	// Algorithms defines the signing algorithms tokens may use. Tokens
+	// signed with any other are rejected before calling KeyFunc.
+	// Optional. Defaults to all the algorithms listed for KeyFunc.
+	Algorithms []string
+
+	// Audience defines the audience tokens must be issued for.
+	// Optional. Defaults to not checking the audience.
+	Audience string
+
+	// Issuer defines the issuer tokens must be issued by.
+	// Optional. Defaults to not checking the issuer.
+	Issuer string
+
+	// Leeway defines the clock skew allowed when checking the expiry and
+	// not before times of tokens.
+	// Optional. Defaults to 0.
+	Leeway time.Duration
+
+	// ClaimsContextKey defines the key that will be used to store the
+	// claims of verified tokens, as a map[string]any, on the echo.Context.
+	// Optional. Defaults to "jwt_claims".
+	ClaimsContextKey string
+}
+
+// DefaultJWTConfig is the default JWT config.
+var DefaultJWTConfig = JWTConfig{
+	Algorithms:       []string{"HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"},
+	ClaimsContextKey: "jwt_claims",
+}
+
+// jwtAuthenticationFunc returns an openapi3filter.AuthenticationFunc
+// verifying the JWT bearer tokens of http bearer security schemes with
+// config and calling next for other security schemes. The claims of
+// verified tokens are stored on the echo.Context.
+func jwtAuthenticationFunc(config JWTConfig, next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	if len(config.Algorithms) == 0 {
+		config.Algorithms = DefaultJWTConfig.Algorithms
+	}
+
+	if config.ClaimsContextKey == "" {
+		config.ClaimsContextKey = DefaultJWTConfig.ClaimsContextKey
+	}
+
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		scheme := input.SecurityScheme
+		if scheme.Type != "http" || !strings.EqualFold(scheme.Scheme, "bearer") ||
+			(scheme.BearerFormat != "" && !strings.EqualFold(scheme.BearerFormat, "JWT")) {
+			return next(ctx, input)
+		}
+
+		auth := input.RequestValidationInput.Request.Header.Get(echo.HeaderAuthorization)
+		if len(auth) <= len("Bearer ") || !strings.EqualFold(auth[:len("Bearer ")], "Bearer ") {
+			return &authError{status: http.StatusUnauthorized, challenge: "Bearer", err: errors.New("missing bearer token")}
+		}
+
+		claims, err := verifyJWT(config, auth[len("Bearer "):], time.Now())
+		if err != nil {
+			return &authError{status: http.StatusUnauthorized, challenge: `Bearer error="invalid_token"`, err: fmt.Errorf("invalid bearer token: %v", err)}
+		}
+
+		if c := echoContext(ctx); c != nil {
+			c.Set(config.ClaimsContextKey, claims)
+		}
+
+		return nil
+	}
+}
+
+// jwtClaims holds the registered claims of a token that are verified.
+type jwtClaims struct {
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+}
+
+// audience is the "aud" claim, either a string or an array of strings.
+type audience []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*a = audience{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(a))
+}
+
+// verifyJWT verifies the signature of token and its claims at now,
+// returning its claims.
+func verifyJWT(config JWTConfig, token string, now time.Time) (map[string]any, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("malformed header: %v", err)
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature: %v", err)
+	}
+
+	if !slices.Contains(config.Algorithms, header.Alg) {
+		return nil, fmt.Errorf("signing algorithm %q is not allowed", header.Alg)
+	}
+
+	key, err := config.KeyFunc(header.Alg, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	err = verifyJWTSignature(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig)
+	if err != nil {
+		return nil, err
+	}
+
+	var claims jwtClaims
+	if err = decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("malformed claims: %v", err)
+	}
+
+	var all map[string]any
+	if err = decodeJWTPart(parts[1], &all); err != nil {
+		return nil, fmt.Errorf("malformed claims: %v", err)
+	}
+
+	if claims.ExpiresAt != nil && !now.Before(unixTime(*claims.ExpiresAt).Add(config.Leeway)) {
+		return nil, errors.New("token is expired")
+	}
+
+	if claims.NotBefore != nil && now.Add(config.Leeway).Before(unixTime(*claims.NotBefore)) {
+		return nil, errors.New("token is not valid yet")
+	}
+
+	if config.Issuer != "" && claims.Issuer != config.Issuer {
+		return nil, fmt.Errorf("token issuer %q is not %q", claims.Issuer, config.Issuer)
+	}
+
+	if config.Audience != "" && !slices.Contains(claims.Audience, config.Audience) {
+		return nil, fmt.Errorf("token is not issued for audience %q", config.Audience)
+	}
+
+	return all, nil
+}
+
+// decodeJWTPart decodes the base64url encoded JSON part of a token into v.
+func decodeJWTPart(part string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// unixTime converts a NumericDate claim to a time.Time.
+func unixTime(seconds float64) time.Time {
+	return time.UnixMilli(int64(seconds * 1000))
+}
+
+// verifyJWTSignature verifies sig is the signature of signed using alg and key.
+func verifyJWTSignature(alg string, key any, signed []byte, sig []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "HS256", "RS256", "ES256":
+		hash = crypto.SHA256
+	case "HS384", "RS384", "ES384":
+		hash = crypto.SHA384
+	case "HS512", "RS512", "ES512":
+		hash = crypto.SHA512
+	case "EdDSA":
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", alg)
+	}
+
+	invalidKey := fmt.Errorf("invalid key type %T for signing algorithm %q", key, alg)
+	invalidSignature := errors.New("signature is invalid")
+
+	if alg == "EdDSA" {
+		k, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return invalidKey
+		}
+		if !ed25519.Verify(k, signed, sig) {
+			return invalidSignature
+		}
+		return nil
+	}
+
+	h := hash.New()
+	h.Write(signed)
+	digest := h.Sum(nil)
+
+	switch alg[:2] {
+	case "HS":
+		k, ok := key.([]byte)
+		if !ok {
+			return invalidKey
+		}
+		mac := hmac.New(hash.New, k)
+		mac.Write(signed)
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return invalidSignature
+		}
+	case "RS":
+		k, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return invalidKey
+		}
+		if rsa.VerifyPKCS1v15(k, hash, digest, sig) != nil {
+			return invalidSignature
+		}
+	case "ES":
+		k, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return invalidKey
+		}
+		size := (k.Curve.Params().BitSize + 7) / 8
+		if len(sig) != 2*size {
+			return invalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:size])
+		s := new(big.Int).SetBytes(sig[size:])
+		if !ecdsa.Verify(k, digest, r, s) {
+			return invalidSignature
+		}
+	}
+
+	return nil
+}
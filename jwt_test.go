@@ -0,0 +1,279 @@
+package openapi
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func signJWT(t *testing.T, alg string, key any, claims map[string]any) string {
+	h, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	assert.NoError(t, err)
+	c, err := json.Marshal(claims)
+	assert.NoError(t, err)
+
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	digest := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, k)
+		mac.Write([]byte(signed))
+		sig = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		sig, err = rsa.SignPKCS1v15(rand.Reader, k, crypto.SHA256, digest[:])
+		assert.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		assert.NoError(t, err)
+		sig = make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestOpenAPI_JWT(t *testing.T) {
+	secret := []byte("secret")
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	keyFunc := func(alg string, kid string) (any, error) {
+		switch alg {
+		case "HS256":
+			return secret, nil
+		case "RS256":
+			return &rsaKey.PublicKey, nil
+		case "ES256":
+			return &ecKey.PublicKey, nil
+		}
+		return nil, errors.New("unknown key")
+	}
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := map[string]any{"aud": "api", "exp": exp}
+
+	testCases := []struct {
+		name          string
+		authorization string
+		statusCode    int
+		errors        []string
+		challenge     string
+	}{
+		{"HS256", "Bearer " + signJWT(t, "HS256", secret, valid), http.StatusOK, nil, ""},
+		{"RS256", "Bearer " + signJWT(t, "RS256", rsaKey, valid), http.StatusOK, nil, ""},
+		{"ES256", "Bearer " + signJWT(t, "ES256", ecKey, valid), http.StatusOK, nil, ""},
+		{"audience array", "Bearer " + signJWT(t, "HS256", secret, map[string]any{"aud": []string{"web", "api"}, "exp": exp}), http.StatusOK, nil, ""},
+		{"missing", "", http.StatusUnauthorized, []string{"missing bearer token"}, "Bearer"},
+		{"wrong scheme", "Basic dXNlcjpwYXNz", http.StatusUnauthorized, []string{"missing bearer token"}, "Bearer"},
+		{"malformed", "Bearer token", http.StatusUnauthorized, []string{"invalid bearer token: malformed token"}, `Bearer error="invalid_token"`},
+		{"wrong key", "Bearer " + signJWT(t, "HS256", []byte("wrong"), valid), http.StatusUnauthorized, []string{"invalid bearer token: signature is invalid"}, `Bearer error="invalid_token"`},
+		{"unknown key", "Bearer " + signJWT(t, "HS512", secret, valid), http.StatusUnauthorized, []string{"invalid bearer token: unknown key"}, `Bearer error="invalid_token"`},
+		{"none", "Bearer " + signJWT(t, "none", nil, valid), http.StatusUnauthorized, []string{"invalid bearer token: signing algorithm 'none' is not allowed"}, `Bearer error="invalid_token"`},
+		{"disallowed algorithm", "Bearer " + signJWT(t, "HS384", secret, valid), http.StatusUnauthorized, []string{"invalid bearer token: signing algorithm 'HS384' is not allowed"}, `Bearer error="invalid_token"`},
+		{"expired", "Bearer " + signJWT(t, "HS256", secret, map[string]any{"aud": "api", "exp": time.Now().Add(-time.Hour).Unix()}), http.StatusUnauthorized, []string{"invalid bearer token: token is expired"}, `Bearer error="invalid_token"`},
+		{"not valid yet", "Bearer " + signJWT(t, "HS256", secret, map[string]any{"aud": "api", "nbf": exp}), http.StatusUnauthorized, []string{"invalid bearer token: token is not valid yet"}, `Bearer error="invalid_token"`},
+		{"wrong audience", "Bearer " + signJWT(t, "HS256", secret, map[string]any{"aud": "web", "exp": exp}), http.StatusUnauthorized, []string{"invalid bearer token: token is not issued for audience 'api'"}, `Bearer error="invalid_token"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var claims map[string]any
+			e.GET("/account", func(c echo.Context) error {
+				claims, _ = c.Get(DefaultJWTConfig.ClaimsContextKey).(map[string]any)
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				JWT: &JWTConfig{
+					KeyFunc:    keyFunc,
+					Algorithms: []string{"HS256", "HS512", "RS256", "ES256"},
+					Audience:   "api",
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/account", nil)
+			if tc.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.challenge, resp.Header().Get(echo.HeaderWWWAuthenticate))
+			if tc.statusCode == http.StatusOK {
+				assert.Equal(t, float64(exp), claims["exp"])
+			}
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_JWT_OtherSchemes(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/secure", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+		JWT: &JWTConfig{
+			KeyFunc: func(alg string, kid string) (any, error) {
+				return nil, errors.New("unknown key")
+			},
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/secure", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestOpenAPI_JWT_KeyFunc(t *testing.T) {
+	assert.Panics(t, func() {
+		OpenAPIWithConfig(Config{Schema: "./fixtures/openapi.yaml", JWT: &JWTConfig{}})
+	})
+}
+
+func TestVerifyJWT_Leeway(t *testing.T) {
+	secret := []byte("secret")
+	config := JWTConfig{
+		KeyFunc:    func(alg string, kid string) (any, error) { return secret, nil },
+		Algorithms: []string{"HS256"},
+		Issuer:     "issuer",
+		Leeway:     time.Minute,
+	}
+	token := signJWT(t, "HS256", secret, map[string]any{"iss": "issuer", "exp": time.Now().Add(-30 * time.Second).Unix()})
+
+	claims, err := verifyJWT(config, token, time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, "issuer", claims["iss"])
+
+	_, err = verifyJWT(config, token, time.Now().Add(time.Minute))
+	assert.EqualError(t, err, "token is expired")
+
+	config.Issuer = "other"
+	_, err = verifyJWT(config, token, time.Now())
+	assert.EqualError(t, err, `token issuer "issuer" is not "other"`)
+
+	config.Algorithms = []string{"RS256"}
+	_, err = verifyJWT(config, token, time.Now())
+	assert.EqualError(t, err, `signing algorithm "HS256" is not allowed`)
+}
+
+const exemptionsSpec = `
+openapi: 3.0.4
+info:
+  version: 1.0.0
+  title: Exemptions API
+paths:
+  /account:
+    get:
+      operationId: getAccount
+      tags: [account]
+      security:
+        - BearerAuth: []
+      responses:
+        '200':
+          description: Successful response
+  /public:
+    get:
+      operationId: getPublic
+      tags: [account]
+      responses:
+        '200':
+          description: Successful response
+components:
+  securitySchemes:
+    BearerAuth:
+      type: http
+      scheme: bearer
+      bearerFormat: JWT
+`
+
+func TestOpenAPI_JWT_Exemptions(t *testing.T) {
+	secret := []byte("secret")
+	token := "Bearer " + signJWT(t, "HS256", secret, map[string]any{"exp": time.Now().Add(time.Hour).Unix()})
+
+	exemptions := map[string]Config{
+		"idempotency": {IdempotencySkipper: IdempotencyKeySkipper(func(key string) bool { return true })},
+		"routes":      {ExemptRoutes: map[string][]string{"/*": {"*"}}},
+		"operations":  {ExemptOperations: []string{"getAccount", "getPublic"}},
+		"tags":        {ExemptTags: []string{"account"}},
+	}
+
+	testCases := []struct {
+		name          string
+		path          string
+		authorization string
+		statusCode    int
+	}{
+		{"missing token", "/account", "", http.StatusUnauthorized},
+		{"invalid token", "/account", "Bearer token", http.StatusUnauthorized},
+		{"valid token", "/account", token, http.StatusOK},
+		{"without security", "/public", "", http.StatusOK},
+	}
+
+	for exemption, config := range exemptions {
+		for _, tc := range testCases {
+			t.Run(exemption+" "+tc.name, func(t *testing.T) {
+				e := echo.New()
+
+				e.GET("/*", func(c echo.Context) error {
+					return c.JSON(http.StatusOK, "ok")
+				})
+
+				config.SchemaBytes = []byte(exemptionsSpec)
+				config.JWT = &JWTConfig{
+					KeyFunc: func(alg string, kid string) (any, error) { return secret, nil },
+				}
+				e.Use(OpenAPIWithConfig(config))
+
+				req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+				req.Header.Set(HeaderIdempotencyKey, "key")
+				if tc.authorization != "" {
+					req.Header.Set(echo.HeaderAuthorization, tc.authorization)
+				}
+				resp := httptest.NewRecorder()
+
+				e.ServeHTTP(resp, req)
+
+				assert.Equal(t, tc.statusCode, resp.Code)
+				if tc.statusCode == http.StatusUnauthorized {
+					assert.NotEmpty(t, resp.Header().Get(echo.HeaderWWWAuthenticate))
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package openapi
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// LazyOpenAPI is a middleware whose specification is loaded in the
+// background, e.g. from a config service that may not be reachable when the
+// process starts.
+type LazyOpenAPI struct {
+	middleware echo.MiddlewareFunc
+	ready      chan struct{}
+}
+
+// NewLazyOpenAPI creates the middleware like NewOpenAPI but returns before
+// the specification is loaded, retrying every LoadRetryInterval until it
+// succeeds or Context is done. Until then, requests are rejected with 503,
+// or passed through without validation if SkipValidationUntilReady is set.
+func NewLazyOpenAPI(config Config) (*LazyOpenAPI, error) {
+	config, err := setup(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.LoadRetryInterval == 0 {
+		config.LoadRetryInterval = DefaultConfig.LoadRetryInterval
+	}
+
+	ctx := config.Context
+
+	var current atomic.Pointer[spec]
+	l := &LazyOpenAPI{
+		middleware: newMiddleware(context.Background(), config, &current),
+		ready:      make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			s, source, err := loadSpec(ctx, config)
+			if err == nil {
+				current.Store(s)
+				close(l.ready)
+				watch(ctx, config, source, &current)
+				return
+			}
+
+			config.Logger.Errorf("failed loading schema, retrying in %s: %v", config.LoadRetryInterval, err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(config.LoadRetryInterval):
+			}
+		}
+	}()
+
+	return l, nil
+}
+
+// Middleware returns the echo.MiddlewareFunc validating requests.
+func (l *LazyOpenAPI) Middleware() echo.MiddlewareFunc {
+	return l.middleware
+}
+
+// Ready returns a channel closed once the specification is loaded.
+func (l *LazyOpenAPI) Ready() <-chan struct{} {
+	return l.ready
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLazyOpenAPI(t *testing.T) {
+	testCases := []struct {
+		name        string
+		skip        bool
+		statusCodes [2]int
+	}{
+		{"reject until ready", false, [2]int{http.StatusServiceUnavailable, http.StatusNotFound}},
+		{"skip until ready", true, [2]int{http.StatusOK, http.StatusNotFound}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var version atomic.Int32
+			var fail atomic.Bool
+			version.Store(1)
+			fail.Store(true)
+
+			ts := newRegistry(&version, &fail)
+			defer ts.Close()
+
+			logger := log.New("test")
+			logger.SetLevel(log.OFF)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			l, err := NewLazyOpenAPI(Config{
+				Context:                  ctx,
+				SchemaURL:                ts.URL,
+				LoadRetryInterval:        10 * time.Millisecond,
+				SkipValidationUntilReady: tc.skip,
+				Logger:                   logger,
+			})
+			assert.NoError(t, err)
+
+			e := echo.New()
+			e.GET("/v2", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+			e.Use(l.Middleware())
+
+			serve := func() int {
+				req := httptest.NewRequest(http.MethodGet, "/v2", nil)
+				resp := httptest.NewRecorder()
+				e.ServeHTTP(resp, req)
+				return resp.Code
+			}
+
+			select {
+			case <-l.Ready():
+				t.Fatal("ready before the schema was loaded")
+			case <-time.After(50 * time.Millisecond):
+			}
+			assert.Equal(t, tc.statusCodes[0], serve())
+
+			fail.Store(false)
+
+			select {
+			case <-l.Ready():
+			case <-time.After(time.Second):
+				t.Fatal("not ready after the schema was available")
+			}
+			assert.Equal(t, tc.statusCodes[1], serve())
+		})
+	}
+}
+
+func TestNewLazyOpenAPI_Error(t *testing.T) {
+	l, err := NewLazyOpenAPI(Config{})
+	assert.EqualError(t, err, "either spec, schema, schemaBytes, schemaReader or schemaURL is required")
+	assert.Nil(t, l)
+}
+
+func TestNewLazyOpenAPI_SkipValidationUntilReady_JWT(t *testing.T) {
+	var version atomic.Int32
+	var fail atomic.Bool
+	fail.Store(true)
+
+	ts := newRegistry(&version, &fail)
+	defer ts.Close()
+
+	logger := log.New("test")
+	logger.SetLevel(log.OFF)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l, err := NewLazyOpenAPI(Config{
+		Context:                  ctx,
+		SchemaURL:                ts.URL,
+		LoadRetryInterval:        10 * time.Millisecond,
+		SkipValidationUntilReady: true,
+		Logger:                   logger,
+		JWT: &JWTConfig{
+			KeyFunc: func(alg string, kid string) (any, error) { return []byte("secret"), nil },
+		},
+	})
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.GET("/v2", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+	e.Use(l.Middleware())
+
+	req := httptest.NewRequest(http.MethodGet, "/v2", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.Code)
+}
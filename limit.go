@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// maxEscapedCharBytes is the size of the JSON escape of a character.
+const maxEscapedCharBytes = len(`\u0000`)
+
+// maxBodyBytes returns the size limit of the request body of req for route
+// or 0 if there's none: max, or the limit derived from the schema of the
+// body when derive is set and it's lower.
+func maxBodyBytes(req *http.Request, route *routers.Route, max int64, derive bool) int64 {
+	if !derive {
+		return max
+	}
+
+	if derived, ok := schemaMaxBytes(requestBodySchema(req, route), make(map[*openapi3.Schema]bool)); ok && (max == 0 || derived < max) {
+		return derived
+	}
+
+	return max
+}
+
+// schemaMaxBytes returns the size of the largest compact JSON value of
+// schema, reporting whether it's bounded: strings need a maxLength or enum,
+// arrays a maxItems and objects additionalProperties: false.
+func schemaMaxBytes(schema *openapi3.Schema, visiting map[*openapi3.Schema]bool) (int64, bool) {
+	if schema == nil || visiting[schema] || len(schema.AllOf) > 0 || len(schema.AnyOf) > 0 || len(schema.OneOf) > 0 {
+		return 0, false
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	var size int64
+	if len(schema.Enum) > 0 {
+		for _, v := range schema.Enum {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return 0, false
+			}
+			size = max(size, int64(len(b))*int64(maxEscapedCharBytes))
+		}
+	} else {
+		switch schema.Type {
+		case openapi3.TypeString:
+			if schema.MaxLength == nil {
+				return 0, false
+			}
+			size = 2 + int64(*schema.MaxLength)*int64(maxEscapedCharBytes)
+		case openapi3.TypeInteger:
+			size = int64(len("-9223372036854775808"))
+		case openapi3.TypeNumber:
+			size = 32
+		case openapi3.TypeBoolean:
+			size = int64(len("false"))
+		case openapi3.TypeArray:
+			if schema.MaxItems == nil || schema.Items == nil {
+				return 0, false
+			}
+			item, ok := schemaMaxBytes(schema.Items.Value, visiting)
+			if !ok {
+				return 0, false
+			}
+			size = 2 + int64(*schema.MaxItems)*(item+1)
+		case openapi3.TypeObject:
+			if has := schema.AdditionalProperties.Has; has == nil || *has {
+				return 0, false
+			}
+			size = 2
+			for name, ref := range schema.Properties {
+				if ref == nil {
+					return 0, false
+				}
+				value, ok := schemaMaxBytes(ref.Value, visiting)
+				if !ok {
+					return 0, false
+				}
+				// "name":value,
+				size += 2 + int64(len(name)*maxEscapedCharBytes) + 1 + value + 1
+			}
+		default:
+			return 0, false
+		}
+	}
+
+	if schema.Nullable {
+		size = max(size, int64(len("null")))
+	}
+
+	return size, true
+}
+
+// limitBody limits the request body of req to limit bytes, reporting
+// whether its declared length exceeds it.
+func limitBody(w http.ResponseWriter, req *http.Request, limit int64) bool {
+	if req.ContentLength > limit {
+		return false
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		req.Body = http.MaxBytesReader(w, req.Body, limit)
+	}
+
+	return true
+}
+
+// isBodyTooLarge reports whether err is from reading a body exceeding its
+// limit.
+func isBodyTooLarge(err error) bool {
+	var mbe *http.MaxBytesError
+	return errors.As(err, &mbe)
+}
+
+// bodyTooLargeIssues returns the issues of a body exceeding limit bytes.
+func bodyTooLargeIssues(limit int64) map[string][]string {
+	return map[string][]string{"body": {fmt.Sprintf("request body exceeds %d bytes", limit)}}
+}
@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_MaxBodyBytes(t *testing.T) {
+	long := `{"code":"` + strings.Repeat("x", 60) + `"}`
+
+	testCases := []struct {
+		name          string
+		path          string
+		maxBodyBytes  int64
+		derive        bool
+		body          string
+		unknownLength bool
+		statusCode    int
+		called        bool
+		errors        []string
+	}{
+		{"within limit", "/optional-body", 100, false, `{"username":"test"}`, false, http.StatusOK, true, nil},
+		{"exceeds limit", "/optional-body", 10, false, `{"username":"test"}`, false, http.StatusRequestEntityTooLarge, false, []string{"request body exceeds 10 bytes"}},
+		{"exceeds limit unknown length", "/optional-body", 10, false, `{"username":"test"}`, true, http.StatusRequestEntityTooLarge, false, []string{"request body exceeds 10 bytes"}},
+		{"exceeds limit read by handler", "/skipped", 10, false, `{"name":"test"}`, true, http.StatusRequestEntityTooLarge, true, []string{"request body exceeds 10 bytes"}},
+		{"derived within limit", "/limited", 0, true, `{"code":"abcd"}`, false, http.StatusOK, true, nil},
+		{"derived exceeds limit", "/limited", 0, true, long, false, http.StatusRequestEntityTooLarge, false, []string{"request body exceeds 56 bytes"}},
+		{"limit lower than derived", "/limited", 20, true, `{"code":"abcd","x":1}`, false, http.StatusRequestEntityTooLarge, false, []string{"request body exceeds 20 bytes"}},
+		{"derived unbounded", "/optional-body", 0, true, long, false, http.StatusOK, true, nil},
+		{"no limit", "/limited", 0, false, long, false, http.StatusUnprocessableEntity, false, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var called bool
+			e.POST(tc.path, func(c echo.Context) error {
+				called = true
+				if _, err := io.ReadAll(c.Request().Body); err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+				}
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:             "./fixtures/openapi.yaml",
+				MaxBodyBytes:       tc.maxBodyBytes,
+				DeriveMaxBodyBytes: tc.derive,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.unknownLength {
+				req.ContentLength = -1
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			assert.Equal(t, tc.called, called)
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestSchemaMaxBytes(t *testing.T) {
+	maxLength := uint64(3)
+	maxItems := uint64(2)
+	forbid := false
+
+	testCases := []struct {
+		name    string
+		schema  *openapi3.Schema
+		size    int64
+		bounded bool
+	}{
+		{"string", &openapi3.Schema{Type: openapi3.TypeString, MaxLength: &maxLength}, 20, true},
+		{"unbounded string", &openapi3.Schema{Type: openapi3.TypeString}, 0, false},
+		{"enum", &openapi3.Schema{Type: openapi3.TypeString, Enum: []any{"a", "abc"}}, 30, true},
+		{"boolean", &openapi3.Schema{Type: openapi3.TypeBoolean}, 5, true},
+		{"nullable boolean", &openapi3.Schema{Type: openapi3.TypeBoolean, Nullable: true}, 5, true},
+		{"array", &openapi3.Schema{Type: openapi3.TypeArray, MaxItems: &maxItems, Items: openapi3.NewSchemaRef("", openapi3.NewBoolSchema())}, 14, true},
+		{"unbounded array", &openapi3.Schema{Type: openapi3.TypeArray, Items: openapi3.NewSchemaRef("", openapi3.NewBoolSchema())}, 0, false},
+		{
+			"object",
+			&openapi3.Schema{
+				Type:                 openapi3.TypeObject,
+				AdditionalProperties: openapi3.AdditionalProperties{Has: &forbid},
+				Properties:           openapi3.Schemas{"ok": openapi3.NewSchemaRef("", openapi3.NewBoolSchema())},
+			},
+			23, true,
+		},
+		{
+			"open object",
+			&openapi3.Schema{
+				Type:       openapi3.TypeObject,
+				Properties: openapi3.Schemas{"ok": openapi3.NewSchemaRef("", openapi3.NewBoolSchema())},
+			},
+			0, false,
+		},
+		{"composed", &openapi3.Schema{AnyOf: openapi3.SchemaRefs{openapi3.NewSchemaRef("", openapi3.NewBoolSchema())}}, 0, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			size, bounded := schemaMaxBytes(tc.schema, make(map[*openapi3.Schema]bool))
+
+			assert.Equal(t, tc.bounded, bounded)
+			assert.Equal(t, tc.size, size)
+		})
+	}
+}
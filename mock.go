@@ -0,0 +1,275 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+)
+
+// ExtensionMock is the vendor extension making the middleware mock the
+// responses of an operation set to true, like MockMode.
+const ExtensionMock = "x-mock"
+
+// mockStatusRequested reports whether the response to the request of c to
+// route should be mocked, per mockMode, the x-mock extension of the
+// operation or the header named header, and the status requested by the
+// header, if any.
+func mockStatusRequested(c echo.Context, route *routers.Route, mockMode bool, header string) (int, bool) {
+	if header != "" {
+		if v := c.Request().Header.Get(header); v != "" {
+			if code, err := strconv.Atoi(v); err == nil {
+				return code, true
+			}
+			if ok, err := strconv.ParseBool(v); err == nil {
+				return 0, ok
+			}
+		}
+	}
+
+	if mock, _ := route.Operation.Extensions[ExtensionMock].(bool); mock {
+		return 0, true
+	}
+
+	return 0, mockMode
+}
+
+// mockResponse responds with the example declared for the response of route
+// with status or, if 0, the lowest declared 2xx status, falling back to
+// "default". The content type is negotiated from the Accept header and,
+// without examples, a value is generated from the schema.
+func mockResponse(c echo.Context, route *routers.Route, status int) error {
+	var response *openapi3.Response
+	if status == 0 {
+		status, response = mockStatus(route.Operation)
+	} else if route.Operation.Responses != nil {
+		if ref := route.Operation.Responses.Status(status); ref != nil && ref.Value != nil {
+			response = ref.Value
+		} else if ref := route.Operation.Responses.Default(); ref != nil && ref.Value != nil {
+			response = ref.Value
+		}
+	}
+	if response == nil {
+		return echo.NewHTTPError(http.StatusNotImplemented, "No response to mock")
+	}
+
+	if len(response.Content) == 0 {
+		return c.NoContent(status)
+	}
+
+	contentType, mediaType := negotiate(response.Content, c.Request().Header.Get(echo.HeaderAccept))
+	example, ok := mediaTypeExample(mediaType)
+	if !ok {
+		return echo.NewHTTPError(http.StatusNotImplemented, "No example to mock")
+	}
+
+	var b []byte
+	if s, ok := example.(string); ok && !strings.Contains(contentType, "json") {
+		b = []byte(s)
+	} else {
+		var err error
+		b, err = json.Marshal(example)
+		if err != nil {
+			return fmt.Errorf("failed marshaling example: %v", err)
+		}
+	}
+
+	return c.Blob(status, contentType, b)
+}
+
+// mockStatus returns the lowest 2xx status declared by op and its response,
+// falling back to the default response with a 200 status.
+func mockStatus(op *openapi3.Operation) (int, *openapi3.Response) {
+	if op.Responses == nil {
+		return 0, nil
+	}
+
+	var statuses []int
+	for k := range op.Responses.Map() {
+		if code, err := strconv.Atoi(k); err == nil && code >= 200 && code < 300 {
+			statuses = append(statuses, code)
+		}
+	}
+	sort.Ints(statuses)
+
+	if len(statuses) > 0 {
+		if ref := op.Responses.Status(statuses[0]); ref != nil && ref.Value != nil {
+			return statuses[0], ref.Value
+		}
+	}
+
+	if ref := op.Responses.Default(); ref != nil && ref.Value != nil {
+		return http.StatusOK, ref.Value
+	}
+
+	return 0, nil
+}
+
+// negotiate returns the first media type of content accepted by accept,
+// falling back to JSON, then to the first declared media type.
+func negotiate(content openapi3.Content, accept string) (string, *openapi3.MediaType) {
+	keys := make([]string, 0, len(content))
+	for k := range content {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, part := range strings.Split(accept, ",") {
+		mt := strings.TrimSpace(strings.Split(part, ";")[0])
+		if mt == "" || mt == "*/*" {
+			continue
+		}
+		if v := content.Get(mt); v != nil {
+			return mt, v
+		}
+	}
+
+	if v, ok := content[ApplicationJSON]; ok {
+		return ApplicationJSON, v
+	}
+
+	return keys[0], content[keys[0]]
+}
+
+// mediaTypeExample returns the example of mediaType, from its example, its
+// first named example, its schema example or generated from its schema.
+func mediaTypeExample(mediaType *openapi3.MediaType) (any, bool) {
+	if mediaType.Example != nil {
+		return mediaType.Example, true
+	}
+
+	names := make([]string, 0, len(mediaType.Examples))
+	for k := range mediaType.Examples {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ref := mediaType.Examples[name]; ref != nil && ref.Value != nil && ref.Value.Value != nil {
+			return ref.Value.Value, true
+		}
+	}
+
+	if mediaType.Schema != nil && mediaType.Schema.Value != nil {
+		return schemaExample(mediaType.Schema.Value, 0), true
+	}
+
+	return nil, false
+}
+
+// maxExampleDepth limits the depth of the values generated from recursive
+// schemas.
+const maxExampleDepth = 8
+
+// schemaExample generates a value of schema, nested depth levels deep,
+// preferring its example, default and first enum value.
+func schemaExample(schema *openapi3.Schema, depth int) any {
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if depth >= maxExampleDepth {
+		return nil
+	}
+
+	for _, refs := range []openapi3.SchemaRefs{schema.OneOf, schema.AnyOf} {
+		if len(refs) > 0 && refs[0].Value != nil {
+			return schemaExample(refs[0].Value, depth+1)
+		}
+	}
+
+	if len(schema.AllOf) > 0 {
+		object := make(map[string]any)
+		for _, ref := range schema.AllOf {
+			if ref.Value == nil {
+				continue
+			}
+			v, ok := schemaExample(ref.Value, depth+1).(map[string]any)
+			if !ok {
+				return schemaExample(ref.Value, depth+1)
+			}
+			for k, v := range v {
+				object[k] = v
+			}
+		}
+		for k, v := range objectExample(schema, depth) {
+			object[k] = v
+		}
+		return object
+	}
+
+	switch schema.Type {
+	case openapi3.TypeString:
+		return stringExample(schema.Format)
+	case openapi3.TypeInteger:
+		if schema.Min != nil {
+			return int64(*schema.Min)
+		}
+		return 0
+	case openapi3.TypeNumber:
+		if schema.Min != nil {
+			return *schema.Min
+		}
+		return 0.0
+	case openapi3.TypeBoolean:
+		return false
+	case openapi3.TypeArray:
+		if schema.Items == nil || schema.Items.Value == nil {
+			return []any{}
+		}
+		return []any{schemaExample(schema.Items.Value, depth+1)}
+	case openapi3.TypeObject, "":
+		if schema.Type == "" && len(schema.Properties) == 0 {
+			return nil
+		}
+		return objectExample(schema, depth)
+	}
+
+	return nil
+}
+
+// objectExample generates an object of the properties of schema, leaving
+// out writeOnly ones.
+func objectExample(schema *openapi3.Schema, depth int) map[string]any {
+	object := make(map[string]any, len(schema.Properties))
+	for name, ref := range schema.Properties {
+		if ref.Value == nil || ref.Value.WriteOnly {
+			continue
+		}
+		object[name] = schemaExample(ref.Value, depth+1)
+	}
+	return object
+}
+
+// stringExample returns an example string of format.
+func stringExample(format string) string {
+	switch format {
+	case "date":
+		return "2006-01-02"
+	case "date-time":
+		return "2006-01-02T15:04:05Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "uri", "url":
+		return "https://example.com"
+	case "hostname":
+		return "example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	}
+	return "string"
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_MockMode(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		accept      string
+		statusCode  int
+		contentType string
+		body        string
+	}{
+		{"json example", "/mock/1", "", http.StatusOK, echo.MIMEApplicationJSON, `{"id":1,"name":"test"}`},
+		{"text schema example", "/mock/1", echo.MIMETextPlain, http.StatusOK, echo.MIMETextPlain, "test"},
+		{"no content", "/no-content", "", http.StatusNoContent, "", ""},
+		{"generated from schema", "/", "", http.StatusOK, echo.MIMEApplicationJSON, `{"message":"string"}`},
+		{"invalid request", "/mock/abc", "", http.StatusUnprocessableEntity, echo.MIMEApplicationJSONCharsetUTF8, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Any("/*", func(c echo.Context) error {
+				return c.String(http.StatusTeapot, "handler")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:   "./fixtures/openapi.yaml",
+				MockMode: true,
+			}))
+
+			method := http.MethodGet
+			if tc.path == "/no-content" {
+				method = http.MethodPost
+			}
+
+			req := httptest.NewRequest(method, tc.path, nil)
+			if tc.accept != "" {
+				req.Header.Set(echo.HeaderAccept, tc.accept)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.contentType, resp.Header().Get(echo.HeaderContentType))
+			if tc.body != "" {
+				assert.Equal(t, tc.body, resp.Body.String())
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_MockHeader(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		header     string
+		statusCode int
+		body       string
+	}{
+		{"no header", "/mock/1", "", http.StatusTeapot, "handler"},
+		{"true", "/mock/1", "true", http.StatusOK, `{"id":1,"name":"test"}`},
+		{"status", "/mock/1", "404", http.StatusNotFound, ""},
+		{"extension", "/mocked", "", http.StatusOK, `{"id":"00000000-0000-0000-0000-000000000000","createdAt":"2006-01-02T15:04:05Z","count":1,"tags":["string"],"status":"active"}`},
+		{"extension disabled by header", "/mocked", "false", http.StatusTeapot, "handler"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Any("/*", func(c echo.Context) error {
+				return c.String(http.StatusTeapot, "handler")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:     "./fixtures/openapi.yaml",
+				MockHeader: "X-Mock",
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.header != "" {
+				req.Header.Set("X-Mock", tc.header)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if strings.HasPrefix(tc.body, "{") {
+				assert.JSONEq(t, tc.body, resp.Body.String())
+			} else {
+				assert.Equal(t, tc.body, resp.Body.String())
+			}
+		})
+	}
+}
@@ -1,19 +1,37 @@
+// Package openapi provides an echo middleware validating requests, and
+// optionally responses, against an OpenAPI 3 specification.
+//
+// kin-openapi keeps string formats and body decoders in process-wide
+// registries, so the ValidateFormats, Formats, DecodeFormBodies and
+// DecodeXMLBodies options of a Config apply to every specification
+// validated in the process once a middleware using them is created. As
+// kin-openapi reads these registries without synchronization, middlewares
+// using Formats must be created before any request is served.
 package openapi
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"net/http"
+	"net/url"
+	"path"
+	"slices"
 	"sort"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/getkin/kin-openapi/routers"
-	"github.com/getkin/kin-openapi/routers/gorillamux"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"github.com/labstack/gommon/log"
 )
 
 type Config struct {
@@ -34,19 +52,601 @@ type Config struct {
 	// If both Schema and SchemaBytes are provided, SchemaBytes takes precedence.
 	SchemaBytes []byte
 
+	// SchemaReader allows loading the OpenAPI specification from an
+	// io.Reader, read once when the middleware is created.
+	// Required unless Schema, SchemaBytes or SchemaURL is provided.
+	SchemaReader io.Reader
+
+	// SchemaFS defines the filesystem Schema and the files it references
+	// with relative $refs are read from, e.g. an embed.FS holding a
+	// multi-file specification.
+	// Optional. Defaults to the OS filesystem.
+	SchemaFS fs.FS
+
+	// Spec defines an OpenAPI document already loaded with kin-openapi,
+	// e.g. after injecting servers or stripping paths. Its schemas are
+	// copied before being adapted, e.g. to AdditionalProperties, so it's
+	// left unchanged and can be shared by several middlewares.
+	// Required unless Schema, SchemaBytes or SchemaURL is provided.
+	//
+	// Spec takes precedence over Schema, SchemaBytes and SchemaURL.
+	Spec *openapi3.T
+
+	// SchemaURL defines the URL the OpenAPI specification will be
+	// fetched from, e.g. a central schema registry.
+	// Required unless Schema or SchemaBytes is provided.
+	SchemaURL string
+
+	// SchemaURLTimeout defines the timeout of requests fetching the
+	// specification from SchemaURL.
+	// Optional. Defaults to 10 seconds.
+	SchemaURLTimeout time.Duration
+
+	// SchemaURLHeaders defines headers sent with requests fetching the
+	// specification from SchemaURL, e.g. an Authorization header.
+	// Optional.
+	SchemaURLHeaders http.Header
+
+	// WatchFile makes the middleware reload the specification when the
+	// Schema file changes on disk, checked every PollInterval, or every
+	// second if unset. The new specification is validated and atomically
+	// replaces the current one, in-flight requests keep using the previous
+	// one. Failed reloads keep the current specification.
+	// Optional. Defaults to false.
+	WatchFile bool
+
+	// PollInterval defines how often the specification is fetched
+	// again from SchemaURL. When it changed, the new specification
+	// atomically replaces the current one. The ETag and Last-Modified
+	// response headers are used to avoid redundant reloads. Failed polls
+	// keep the current specification.
+	// Optional. Defaults to 0, polling disabled.
+	PollInterval time.Duration
+
+	// LoadRetryInterval defines how often NewLazyOpenAPI retries loading
+	// the specification until it succeeds.
+	// Optional. Defaults to 5 seconds.
+	LoadRetryInterval time.Duration
+
+	// SkipValidationUntilReady makes the middleware created by
+	// NewLazyOpenAPI pass requests through without validation until the
+	// specification is loaded, rather than rejecting them with 503. As
+	// security requirements are unknown until then, requests are still
+	// rejected when credentials are verified, e.g. with JWT. So are
+	// exempted requests.
+	// Optional. Defaults to false.
+	SkipValidationUntilReady bool
+
+	// Context bounds the background work of the middleware: polling the
+	// specification, with WatchFile or PollInterval, and the retries of
+	// NewLazyOpenAPI stop once it's done. It's also used to load the
+	// specification.
+	// Optional. Defaults to context.Background(), never stopping.
+	Context context.Context
+
+	// SpecPath defines the path the loaded specification is served at, as
+	// JSON, or YAML if it ends with ".yaml" or ".yml". It's also served as
+	// YAML with its extension replaced by ".yaml" or ".yml", e.g.
+	// "/openapi.yaml" for "/openapi.json".
+	// Optional. Defaults to "", not served.
+	SpecPath string
+
+	// SpecServersFromRequest makes the specification served at SpecPath
+	// use the scheme and host of the request in the URLs of its servers,
+	// keeping their path.
+	// Optional. Defaults to false.
+	SpecServersFromRequest bool
+
+	// IdempotencySkipper defines a function to skip validation of replayed
+	// requests, e.g. requests carrying an Idempotency-Key that was already
+	// validated and processed. Replay semantics are left to the handler.
+	// The security requirements of the operation are still enforced.
+	// See IdempotencyKeySkipper.
+	// Optional.
+	IdempotencySkipper middleware.Skipper
+
 	// ContextKey defines the key that will be used to store the validator
 	// on the echo.Context when the request is successfully validated.
 	// Optional. Defaults to "validator".
 	ContextKey string
 
 	// ExemptRoutes defines routes and methods that don't require validation.
+	// Routes are echo paths or glob patterns of them: "*" matches within a
+	// path segment, e.g. "/internal/*", and "**" matches any number of
+	// segments, e.g. "/debug/**". The "*" method matches every method.
+	// The security requirements of their operations are still enforced.
 	// Optional.
 	ExemptRoutes map[string][]string
+
+	// ExemptOperations defines the operationIds of the operations that don't
+	// require validation. Unlike ExemptRoutes, they match the spec rather
+	// than echo paths, so they survive path changes. Their security
+	// requirements are still enforced.
+	// Optional.
+	ExemptOperations []string
+
+	// ExemptTags defines the tags of the operations that don't require
+	// validation, exempting every operation with any of them. Their
+	// security requirements are still enforced.
+	// Optional.
+	ExemptTags []string
+
+	// ValidateErrorResponses makes the middleware validate the body of an
+	// *echo.HTTPError returned by the handler against the response schema
+	// declared for its status code. The body is rendered the same way as
+	// echo's default HTTPErrorHandler.
+	// Optional. Defaults to false.
+	ValidateErrorResponses bool
+
+	// LenientOptionalBody treats a body sent without a Content-Type header
+	// to an operation whose request body is optional as if no body was
+	// present, instead of rejecting the request: the body is neither
+	// validated nor passed to the handler, which reads an empty body.
+	// Optional. Defaults to false.
+	LenientOptionalBody bool
+
+	// ParseTimeFormats makes the middleware decode the validated JSON
+	// request body and convert every field declared with format "date" or
+	// "date-time" into a time.Time. The result is stored on the
+	// echo.Context under TypedBodyContextKey.
+	// Optional. Defaults to false.
+	ParseTimeFormats bool
+
+	// TypedBodyContextKey defines the key that will be used to store the
+	// decoded request body when ParseTimeFormats is enabled.
+	// Optional. Defaults to "typed_body".
+	TypedBodyContextKey string
+
+	// SkipDefaults stops the middleware from setting the defaults declared
+	// by the spec on requests. By default, missing query, header and cookie
+	// parameters are added with their default and properties missing from
+	// JSON request bodies are added to the body, so handlers see them.
+	// Optional. Defaults to false.
+	SkipDefaults bool
+
+	// DefaultsContextKey defines the key that will be used to store the
+	// defaults set on the query, header and cookie parameters of the
+	// request, as a map[string]any keyed like "query.limit".
+	// Optional. Defaults to not storing them.
+	DefaultsContextKey string
+
+	// TypedParams makes the middleware store the validated path, query,
+	// header and cookie parameters, decoded according to the spec, as
+	// *Params on the echo.Context under ParamsContextKey, read by helpers
+	// like QueryInt. Path parameters are keyed by their name in the spec,
+	// regardless of the names of the echo route.
+	// Optional. Defaults to false.
+	TypedParams bool
+
+	// ReadOnlyProperties defines how readOnly properties in request bodies
+	// are handled: PropertyReject fails validation, PropertyStrip removes
+	// them from JSON bodies before validation and PropertyIgnore allows
+	// them.
+	// Optional. Defaults to PropertyReject.
+	ReadOnlyProperties PropertyMode
+
+	// AdditionalProperties overrides the additionalProperties of every
+	// object schema of the specification: AdditionalPropertiesForbid
+	// rejects unknown fields as if each specified false and
+	// AdditionalPropertiesAllow accepts them as if each specified true.
+	// Schemas composed with allOf, anyOf or oneOf are left as is.
+	// Optional. Defaults to AdditionalPropertiesAsIs.
+	AdditionalProperties AdditionalPropertiesPolicy
+
+	// DevMode makes request validation failures render as a human-readable,
+	// multi-line text report including the field, the failed constraint and
+	// the received value. Meant for local development only.
+	// Optional. Defaults to false.
+	DevMode bool
+
+	// AllowEmptyPaths allows loading a spec that defines no paths. Such a
+	// spec rejects every request, so by default loading one panics to catch
+	// misconfigured specs early. When allowed, a warning is logged instead.
+	// Optional. Defaults to false.
+	AllowEmptyPaths bool
+
+	// ValidateExamples makes loading the spec check every example, of
+	// schemas, parameters, headers and media types, against its schema
+	// and fail listing all the mismatches with their location, rather than
+	// only the first one.
+	// Optional. Defaults to false.
+	ValidateExamples bool
+
+	// Logger defines the logger used for messages emitted outside of a
+	// request, e.g. while loading the spec.
+	// Optional. Defaults to a logger writing to stdout.
+	Logger echo.Logger
+
+	// OnValidated defines a function called after a request was
+	// successfully validated, before the next handler runs. Useful to
+	// sample traffic for analytics.
+	// Optional.
+	OnValidated func(c echo.Context, input *openapi3filter.RequestValidationInput)
+
+	// TraceIDExtractor defines a function returning the trace id of the
+	// current request, e.g. from the active OpenTelemetry span. When set,
+	// the id is included in validation error responses as "traceId".
+	// Optional.
+	TraceIDExtractor func(c echo.Context) string
+
+	// Tracer defines the Tracer recording a span for the validation of
+	// requests and, when ValidateResponses or AsyncResponseValidation is
+	// enabled, responses, with their operationId, spec path, method,
+	// outcome and error count as attributes.
+	// Optional.
+	Tracer Tracer
+
+	// Coverage defines the Coverage recording the operations, and their
+	// documented responses, exercised by requests. See NewCoverage.
+	// Optional.
+	Coverage *Coverage
+
+	// MissingBodyMessage defines the error message returned when a
+	// required request body is missing.
+	// Optional. Defaults to "request body has an error: value is required but missing".
+	MissingBodyMessage string
+
+	// SummaryHeader defines the response header that will be set to the
+	// summary of the matched operation, e.g. "X-Operation-Summary". Meant
+	// for development, to see which operation handled a request.
+	// Optional. Defaults to "", no header.
+	SummaryHeader string
+
+	// FieldNameCase defines how the field paths of request body errors are
+	// written, e.g. to return camelCase names for a snake_case spec.
+	// Optional. Defaults to CaseAsIs.
+	FieldNameCase FieldNameCase
+
+	// OptionalOverrides defines required request body fields that are
+	// temporarily treated as optional, keyed by operationId. Fields are
+	// dot-separated paths, e.g. "address.city". Useful during a phased
+	// rollout of a new required field.
+	// Optional.
+	OptionalOverrides map[string][]string
+
+	// ErrorDetail defines how much detail validation error responses
+	// contain. ErrorDetailFull adds a "details" list describing the field,
+	// location, failed keyword, message and value of each issue and
+	// ErrorDetailStructured returns that list instead of the "errors".
+	// Optional. Defaults to ErrorDetailBasic.
+	ErrorDetail ErrorDetail
+
+	// CatchAllHandler defines a handler invoked for requests whose path
+	// matches no path of the spec, including paths partially matching a
+	// templated path, instead of returning 404. Useful to serve an SPA or
+	// a custom 404 page.
+	// Optional.
+	CatchAllHandler echo.HandlerFunc
+
+	// SoftTimeout defines a validation time budget. Requests whose
+	// validation takes longer are still validated, but OnSlowValidation
+	// is called, to find pathological schemas without rejecting requests.
+	// Optional. Defaults to 0, disabled.
+	SoftTimeout time.Duration
+
+	// OnSlowValidation defines a function called with the operationId and
+	// the duration of request validations exceeding SoftTimeout.
+	// Optional.
+	OnSlowValidation func(operationID string, d time.Duration)
+
+	// MockMode makes the middleware respond with the example declared in
+	// the spec for the matched operation after validating the request,
+	// instead of calling the next handler. The lowest declared 2xx status
+	// is used and the content type is negotiated from the Accept header.
+	// Meant for local development, e.g. for front-end developers.
+	// Responses without examples are generated from their schema.
+	// Operations can also be mocked individually with the x-mock
+	// extension set to true.
+	// Optional. Defaults to false.
+	MockMode bool
+
+	// MockHeader defines the name of a request header making the middleware
+	// mock the response to the request, like MockMode, when set to true,
+	// or with the response declared for the status it's set to, e.g. 404.
+	// Set to false, it disables mocking of the request.
+	// Optional. Defaults to "", disabled.
+	MockHeader string
+
+	// SchemaValidators defines validators for business rules JSON Schema
+	// can't express, e.g. "endDate after startDate", keyed by name. A
+	// schema opts in with the "x-validation" extension set to a name, and
+	// the validator is called with the decoded JSON value of the request
+	// body matching that schema once it passed schema validation. Returned
+	// errors are reported as validation errors. Once set, loading fails if
+	// a schema names a validator that isn't defined.
+	// Optional.
+	SchemaValidators map[string]func(body any) error
+
+	// UseEchoErrorFormat makes validation failures return an
+	// *echo.HTTPError instead of writing the response, so the
+	// echo.HTTPErrorHandler formats them like any other error. Its message
+	// is a map holding the "message" and "errors" of the failure, and the
+	// "details" and "traceId" when set.
+	// Optional. Defaults to false.
+	UseEchoErrorFormat bool
+
+	// ErrorHandler defines a function handling validation failures instead
+	// of the middleware, with the status it would respond and the issues
+	// keyed by field, e.g. "query.limit", or "body" and "security" for
+	// failures of the request body and security requirements. It takes
+	// precedence over UseEchoErrorFormat, ErrorDetail and TraceIDExtractor.
+	// Optional. Defaults to nil.
+	ErrorHandler func(c echo.Context, status int, issues map[string][]string) error
+
+	// OnValidationError defines a function called with the issues of
+	// validation failures, keyed like for ErrorHandler, before the error
+	// response is written, e.g. to emit structured logs. It's also called
+	// in DevMode and when ErrorHandler is set.
+	// Optional.
+	OnValidationError func(c echo.Context, issues map[string][]string)
+
+	// FailFast makes the middleware stop validating requests at the first
+	// issue, which only is returned, instead of collecting all of them.
+	// Optional. Defaults to false.
+	FailFast bool
+
+	// StatusCodes overrides the statuses of failures, keyed by the status
+	// the middleware responds by default: 400 for malformed requests, 422
+	// for invalid ones, 404 and 405 for requests matching no operation,
+	// 401 and 403 for failed security requirements, 413 for bodies too large
+	// and 415 for unsupported media types. E.g. {422: 400} responds 400 to any invalid request.
+	// Optional.
+	StatusCodes map[int]int
+
+	// MaxBodyBytes defines the maximum size, in bytes, of request bodies.
+	// Bodies exceeding it are rejected with 413 before being fully read,
+	// whether by validation or the handler.
+	// Optional. Defaults to 0, no limit.
+	MaxBodyBytes int64
+
+	// DeriveMaxBodyBytes makes the middleware derive the maximum size of JSON
+	// request bodies from their schema, when all of its strings declare a
+	// maxLength or enum, its arrays a maxItems and its objects
+	// additionalProperties: false, and enforce it like MaxBodyBytes when
+	// lower. Derived sizes allow for escaped characters but not for
+	// whitespace between values.
+	// Optional. Defaults to false.
+	DeriveMaxBodyBytes bool
+
+	// DecompressBodies makes the middleware decompress gzip and deflate
+	// encoded request bodies, per their Content-Encoding header, before
+	// validating them. Handlers then read the decompressed body, the
+	// Content-Encoding header being removed. Bodies in other encodings are
+	// left as is.
+	// Optional. Defaults to false.
+	DecompressBodies bool
+
+	// MaxDecompressedBytes defines the maximum size, in bytes, of
+	// decompressed request bodies, guarding against decompression bombs.
+	// Bodies exceeding it are rejected with 413, like with MaxBodyBytes,
+	// which also applies to decompressed bodies when lower.
+	// Optional. Defaults to 10 MiB.
+	MaxDecompressedBytes int64
+
+	// MaxBodyProperties defines the maximum number of properties of any
+	// object of a JSON request body, e.g. one allowing additionalProperties.
+	// Bodies exceeding it are rejected with 422 before being validated,
+	// guarding against clients sending thousands of keys.
+	// Optional. Defaults to 0, no limit.
+	MaxBodyProperties int
+
+	// StreamBodyThreshold defines the size, in bytes, above which JSON
+	// request bodies whose schema is an array, e.g. of a bulk import, are
+	// validated item by item while the handler reads them instead of being
+	// buffered and validated before it's called. Bodies of unknown size are
+	// streamed too. Once an item fails validation, reading the body fails
+	// and the middleware responds 422, unless the handler already
+	// responded. Parts of the body the handler doesn't read aren't
+	// validated and uniqueItems isn't enforced. Streamed bodies are neither
+	// run through SchemaValidators, parsed by ParseTimeFormats nor stripped
+	// by ReadOnlyProperties.
+	// Optional. Defaults to 0, bodies are always buffered.
+	StreamBodyThreshold int64
+
+	// RejectUnknownQueryParams makes the middleware reject requests with
+	// query parameters not declared by the matched operation or its path,
+	// e.g. a misspelled ?pageSize= instead of ?page_size=, with 400.
+	// Optional. Defaults to false.
+	RejectUnknownQueryParams bool
+
+	// TenantConfigResolver defines a function returning the RouteOption
+	// of the current request, e.g. derived from a tenant header or
+	// subdomain, to vary strictness per tenant. A nil RouteOption keeps
+	// the defaults.
+	// Optional.
+	TenantConfigResolver func(c echo.Context) *RouteOption
+
+	// IgnoreHost makes routing ignore the scheme and host of the servers
+	// of the spec and match requests on their path only. Useful behind
+	// load balancers sending unexpected or missing Host headers.
+	// Optional. Defaults to false.
+	IgnoreHost bool
+
+	// AllowedHosts defines other hosts the spec is served on, e.g. virtual
+	// hosts, requests to which are matched on their path only, like with
+	// IgnoreHost, while requests to other hosts must still match the scheme
+	// and host of the servers. Hosts may include a port, matching only that
+	// port, and start with "*." to match any subdomain, e.g.
+	// "*.example.org". Ignored with IgnoreHost, StripPathPrefix,
+	// StripServerPrefixes and RouterFunc.
+	// Optional.
+	AllowedHosts []string
+
+	// StripPathPrefix defines a prefix, e.g. "/api/v2", stripped from request
+	// paths before they're matched against the spec paths, for specs whose
+	// paths omit where the API is mounted. Servers of the spec are then
+	// ignored when matching and requests not under the prefix match no
+	// operation.
+	// Optional.
+	StripPathPrefix string
+
+	// StripServerPrefixes makes the middleware strip the path of the spec
+	// servers, at any level, e.g. "/api/v2" for "https://example.com/api/v2",
+	// from request paths before matching them against the spec paths,
+	// regardless of the scheme and host of the servers. Server path
+	// variables, like "/api/{version}", match any segment. Requests under
+	// none of them match no operation.
+	// Optional. Defaults to false.
+	StripServerPrefixes bool
+
+	// RouteCacheSize defines the number of routes, keyed by method, host
+	// and echo path, kept in an LRU cache to skip matching requests against
+	// the spec paths. Only echo paths without wildcards whose parameters
+	// match those of the spec path are cached, unless the spec has other
+	// paths requests to the echo path could match. The cache is emptied when
+	// the spec is reloaded.
+	// Optional. Defaults to 0, no caching.
+	RouteCacheSize int
+
+	// UseEchoRouter makes the middleware match requests to operations with
+	// the route echo already matched rather than routing them again against
+	// the spec paths. The echo path, like "/users/:id", optionally prefixed
+	// with the path of a server, is mapped to the spec path with parameters
+	// at the same positions, like "/users/{id}", and the echo path
+	// parameters become the path parameters, in order.
+	// Optional. Defaults to false.
+	UseEchoRouter bool
+
+	// RouterFunc defines a function creating the router matching requests
+	// against the spec, e.g. kin-openapi's legacy.NewRouter, called again
+	// whenever the spec is reloaded. IgnoreHost, AllowedHosts and
+	// operation-level servers are left to the router.
+	// Optional. Defaults to a gorillamux router.
+	RouterFunc func(doc *openapi3.T) (routers.Router, error)
+
+	// AsyncResponseValidation makes the middleware validate responses in
+	// the background after they were sent to the client, for monitoring
+	// without enforcement. Mismatches are logged with Logger and reported
+	// to OnResponseValidationError.
+	// Optional. Defaults to false.
+	AsyncResponseValidation bool
+
+	// ValidateResponses makes the middleware buffer responses and validate
+	// their status, headers and body once the handler returns, without the
+	// handler calling Handler.Validate. Valid responses are then sent and
+	// invalid ones replaced with a 500 error. Flushing is deferred until
+	// validation, so it's unsuited to streamed responses.
+	// Optional. Defaults to false.
+	ValidateResponses bool
+
+	// OnResponseValidationError defines a function called with the
+	// operationId and the error of responses failing validation when
+	// AsyncResponseValidation is enabled. It's called from another
+	// goroutine.
+	// Optional.
+	OnResponseValidationError func(operationID string, err error)
+
+	// IgnoredHeaders defines header parameters excluded from validation,
+	// e.g. "X-Forwarded-For" or internal auth headers set by proxies that
+	// would otherwise fail the constraints the spec declares for them.
+	// Names are case-insensitive.
+	// Optional.
+	IgnoredHeaders []string
+
+	// LoaderOptions defines a function customizing the openapi3.Loader
+	// before the spec is loaded, e.g. to set a custom ReadFromURIFunc
+	// resolving external refs.
+	// Optional.
+	LoaderOptions func(loader *openapi3.Loader)
+
+	// ConvertSwagger2 makes the middleware convert Swagger 2.0 documents
+	// to OpenAPI 3 when loading them, so legacy specs can be used as is.
+	// Optional. Defaults to false.
+	ConvertSwagger2 bool
+
+	// AuthenticationFunc defines the function validating the security
+	// requirements of operations, called for each security scheme of a
+	// requirement. Requests meeting none of their requirements are
+	// rejected with 401.
+	// Optional. Defaults to openapi3filter.NoopAuthenticationFunc.
+	AuthenticationFunc openapi3filter.AuthenticationFunc
+
+	// JWT enables verifying the JWT bearer tokens of requests to operations
+	// requiring an http bearer security scheme: their signature, expiry and
+	// audience. The claims of verified tokens are stored on the
+	// echo.Context. Other security schemes are left to AuthenticationFunc.
+	// Optional. Defaults to nil.
+	JWT *JWTConfig
+
+	// APIKeyValidator defines the function verifying the keys of apiKey
+	// security schemes, called with the name of the scheme and the key read
+	// from the header, query parameter or cookie it declares. Requests
+	// missing the key, or with a key it doesn't validate, are rejected with
+	// 401, or the status of the *echo.HTTPError it returns, e.g. 403.
+	// Optional. Defaults to nil.
+	APIKeyValidator func(name string, key string, c echo.Context) (bool, error)
+
+	// ScopesFunc defines the function checking the request has the scopes
+	// required by a security requirement listing scopes, e.g. against the
+	// scopes extracted by an upstream auth middleware. It is called once
+	// the security scheme is authenticated and requests it returns an
+	// error for are rejected with 403.
+	// Optional. Defaults to nil.
+	ScopesFunc func(c echo.Context, required []string) error
+
+	// BasicAuthValidator defines the function verifying the credentials of
+	// http basic security schemes. Requests missing credentials, or with
+	// credentials it doesn't validate, are rejected with 401 and a
+	// WWW-Authenticate header.
+	// Optional. Defaults to nil.
+	BasicAuthValidator middleware.BasicAuthValidator
+
+	// BasicAuthRealm defines the realm of the WWW-Authenticate header of
+	// requests rejected by BasicAuthValidator.
+	// Optional. Defaults to "Restricted".
+	BasicAuthRealm string
+
+	// HonorMethodOverride makes the middleware match and validate requests
+	// carrying the X-HTTP-Method-Override header against the operation of
+	// the overridden method, e.g. a POST overridden to DELETE against the
+	// DELETE operation.
+	// Optional. Defaults to false.
+	HonorMethodOverride bool
+
+	// AutoGenerateHeaders defines functions generating the value of
+	// required header parameters missing from requests, keyed by header
+	// name, e.g. "X-Correlation-ID". Generated values are set on the
+	// request before validation, so handlers can read them. Meant as a
+	// lenient aid while migrating clients.
+	// Optional.
+	AutoGenerateHeaders map[string]func() string
+
+	// ValidateFormats enables validation of the email, uri, hostname, ipv4
+	// and ipv6 string formats.
+	// Optional. Defaults to false.
+	ValidateFormats bool
+
+	// Formats defines validators for custom string formats, e.g. "uuid" or
+	// "e164", keyed by format name, so the format constraints of the spec
+	// are enforced. They're registered when the middleware is created,
+	// which must happen before any request is served.
+	// Optional.
+	Formats map[string]func(value string) error
+
+	// DecodeFormBodies replaces the decoding of application/x-www-form-urlencoded
+	// request bodies, so they're validated against the schema of the
+	// operation: missing optional fields are allowed, values are converted
+	// to the type of their property and array and object fields are decoded
+	// according to the style of their encoding.
+	// Optional. Defaults to false.
+	DecodeFormBodies bool
+
+	// DecodeXMLBodies enables decoding of application/xml and text/xml
+	// request bodies, honoring the xml object of their schema, so they're
+	// validated against the schema of the operation.
+	// Optional. Defaults to false.
+	DecodeXMLBodies bool
 }
 
 var DefaultConfig = Config{
-	Skipper:    middleware.DefaultSkipper,
-	ContextKey: "validator",
+	Skipper:              middleware.DefaultSkipper,
+	ContextKey:           "validator",
+	TypedBodyContextKey:  "typed_body",
+	MissingBodyMessage:   "request body has an error: value is required but missing",
+	SchemaURLTimeout:     10 * time.Second,
+	LoadRetryInterval:    5 * time.Second,
+	BasicAuthRealm:       "Restricted",
+	MaxDecompressedBytes: 10 << 20,
 }
 
 func OpenAPI(file string) echo.MiddlewareFunc {
@@ -61,131 +661,777 @@ func OpenAPIFromBytes(schemaBytes []byte) echo.MiddlewareFunc {
 	return OpenAPIWithConfig(c)
 }
 
-func OpenAPIWithConfig(config Config) echo.MiddlewareFunc {
-	if config.Skipper == nil {
-		config.Skipper = DefaultConfig.Skipper
+func OpenAPIFromFS(fsys fs.FS, path string) echo.MiddlewareFunc {
+	c := DefaultConfig
+	c.SchemaFS = fsys
+	c.Schema = path
+	return OpenAPIWithConfig(c)
+}
+
+func OpenAPIFromURL(schemaURL string) echo.MiddlewareFunc {
+	c := DefaultConfig
+	c.SchemaURL = schemaURL
+	return OpenAPIWithConfig(c)
+}
+
+func OpenAPIWithConfig(config Config) echo.MiddlewareFunc {
+	m, err := NewOpenAPI(config)
+	if err != nil {
+		panic(err.Error())
+	}
+	return m
+}
+
+// NewOpenAPI creates the middleware like OpenAPIWithConfig but returns an
+// error, rather than panicking, when config is invalid or the spec can't be
+// loaded.
+func NewOpenAPI(config Config) (echo.MiddlewareFunc, error) {
+	config, err := setup(config)
+	if err != nil {
+		return nil, err
+	}
+
+	s, source, err := loadSpec(config.Context, config)
+	if err != nil {
+		return nil, err
+	}
+
+	var current atomic.Pointer[spec]
+	current.Store(s)
+
+	watch(config.Context, config, source, &current)
+
+	return newMiddleware(context.Background(), config, &current), nil
+}
+
+// setup validates config and sets its defaults.
+func setup(config Config) (Config, error) {
+	if config.Skipper == nil {
+		config.Skipper = DefaultConfig.Skipper
+	}
+
+	if config.Context == nil {
+		config.Context = context.Background()
+	}
+
+	if err := checkSource(config); err != nil {
+		return config, err
+	}
+
+	if config.ContextKey == "" {
+		config.ContextKey = DefaultConfig.ContextKey
+	}
+
+	if config.Logger == nil {
+		config.Logger = log.New("openapi")
+	}
+
+	if config.TypedBodyContextKey == "" {
+		config.TypedBodyContextKey = DefaultConfig.TypedBodyContextKey
+	}
+
+	if config.MissingBodyMessage == "" {
+		config.MissingBodyMessage = DefaultConfig.MissingBodyMessage
+	}
+
+	if config.MaxDecompressedBytes == 0 {
+		config.MaxDecompressedBytes = DefaultConfig.MaxDecompressedBytes
+	}
+
+	if config.AuthenticationFunc == nil {
+		config.AuthenticationFunc = openapi3filter.NoopAuthenticationFunc
+	}
+
+	if config.JWT != nil {
+		if config.JWT.KeyFunc == nil {
+			return config, errors.New("jwt keyFunc is required")
+		}
+		config.AuthenticationFunc = jwtAuthenticationFunc(*config.JWT, config.AuthenticationFunc)
+	}
+
+	if config.APIKeyValidator != nil {
+		config.AuthenticationFunc = apiKeyAuthenticationFunc(config.APIKeyValidator, config.AuthenticationFunc)
+	}
+
+	if config.BasicAuthValidator != nil {
+		if config.BasicAuthRealm == "" {
+			config.BasicAuthRealm = DefaultConfig.BasicAuthRealm
+		}
+		config.AuthenticationFunc = basicAuthenticationFunc(config.BasicAuthValidator, config.BasicAuthRealm, config.AuthenticationFunc)
+	}
+
+	if config.ScopesFunc != nil {
+		config.AuthenticationFunc = scopesAuthenticationFunc(config.ScopesFunc, config.AuthenticationFunc)
+	}
+
+	registerExtensions(config)
+
+	return config, nil
+}
+
+// checkSource returns an error if config sets no specification source.
+func checkSource(config Config) error {
+	if config.Spec == nil && config.Schema == "" && len(config.SchemaBytes) == 0 && config.SchemaReader == nil && config.SchemaURL == "" {
+		return errors.New("either spec, schema, schemaBytes, schemaReader or schemaURL is required")
+	}
+	return nil
+}
+
+// registerExtensions registers the string formats and body decoders
+// enabled by config in the process-wide registries of kin-openapi.
+func registerExtensions(config Config) {
+	if config.ValidateFormats {
+		defineFormats()
+	}
+
+	for name, validate := range config.Formats {
+		openapi3.DefineStringFormatCallback(name, validate)
+	}
+
+	if config.DecodeFormBodies {
+		registerFormBodyDecoder()
+	}
+
+	if config.DecodeXMLBodies {
+		registerXMLBodyDecoder()
+	}
+}
+
+// watch polls source for changes of the spec, if it can be reloaded.
+func watch(ctx context.Context, config Config, source schemaSource, current *atomic.Pointer[spec]) {
+	if source == nil {
+		return
+	}
+
+	interval := config.PollInterval
+	if interval == 0 && config.WatchFile {
+		interval = time.Second
+	}
+	if interval > 0 {
+		go poll(ctx, config, source, current, interval)
+	}
+}
+
+// newMiddleware creates the middleware validating requests against the spec
+// in current.
+func newMiddleware(ctx context.Context, config Config, current *atomic.Pointer[spec]) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			if config.Skipper(c) {
+				return next(c)
+			}
+
+			// exempted requests are still checked against the security
+			// requirements of their operation
+			exempt := (config.IdempotencySkipper != nil && config.IdempotencySkipper(c)) ||
+				check(c.Path(), c.Request().Method, config.ExemptRoutes)
+
+			req := c.Request()
+			if config.HonorMethodOverride {
+				req = withMethodOverride(req)
+			}
+
+			s := current.Load()
+			if s == nil {
+				// without the spec, security requirements are unknown
+				if (exempt || config.SkipValidationUntilReady) && !authenticates(config) {
+					return next(c)
+				}
+				return echo.NewHTTPError(statusCode(config, http.StatusServiceUnavailable), "Specification not loaded")
+			}
+
+			if !exempt && config.SpecPath != "" && (req.Method == http.MethodGet || req.Method == http.MethodHead) {
+				if format, ok := specFormat(config.SpecPath, req.URL.Path); ok {
+					return serveSpec(c, s.schema, format, config.SpecServersFromRequest)
+				}
+			}
+
+			route, pathParams, err := s.findRoute(c, req, config.IgnoreHost)
+			if err != nil {
+				if exempt {
+					return next(c)
+				}
+
+				c.Logger().Debugf(
+					"error finding route for %s %s: %v",
+					req.Method, req.URL.String(), err,
+				)
+
+				if errors.Is(err, routers.ErrPathNotFound) {
+					if config.CatchAllHandler != nil {
+						return config.CatchAllHandler(c)
+					}
+					return echo.NewHTTPError(statusCode(config, http.StatusNotFound), "Path not found")
+				}
+
+				if errors.Is(err, routers.ErrMethodNotAllowed) {
+					return echo.NewHTTPError(statusCode(config, http.StatusMethodNotAllowed), "Method not allowed")
+				}
+
+				return err
+			}
+
+			if config.Coverage != nil {
+				defer func() { config.Coverage.record(route, responseStatus(c, err)) }()
+			}
+
+			if exempt || slices.Contains(config.ExemptOperations, route.Operation.OperationID) ||
+				slices.ContainsFunc(route.Operation.Tags, func(tag string) bool { return slices.Contains(config.ExemptTags, tag) }) {
+				if ok, err := authorizeExempt(ctx, c, config, req, route, pathParams); !ok {
+					return err
+				}
+				return next(c)
+			}
+
+			skipRequest, skipResponse := skipValidation(route.Operation)
+
+			limit := maxBodyBytes(req, route, config.MaxBodyBytes, config.DeriveMaxBodyBytes)
+			bodyTooLarge := func() error {
+				issues := bodyTooLargeIssues(limit)
+				var details []FieldError
+				if config.ErrorDetail.fields() {
+					details = []FieldError{{In: "body", Code: "maxBytes", Message: issues["body"][0]}}
+				}
+				return validationError(c, config, http.StatusRequestEntityTooLarge, http.StatusText(http.StatusRequestEntityTooLarge), issues, details)
+			}
+			if config.DecompressBodies {
+				decompressed, err := decompressBody(req)
+				if err != nil {
+					msg := fmt.Sprintf("request body has an error: failed to decompress request body: %v", err)
+					var details []FieldError
+					if config.ErrorDetail.fields() {
+						details = []FieldError{{In: "body", Code: "invalid", Message: msg}}
+					}
+					return validationError(c, config, http.StatusBadRequest, "Request error", map[string][]string{"body": {msg}}, details)
+				}
+				if decompressed {
+					c.Request().Body = req.Body
+					c.Request().ContentLength = req.ContentLength
+					if limit == 0 || config.MaxDecompressedBytes < limit {
+						limit = config.MaxDecompressedBytes
+					}
+				}
+			}
+			if limit > 0 {
+				if !limitBody(c.Response().Writer, req, limit) {
+					return bodyTooLarge()
+				}
+				c.Request().Body = req.Body
+			}
+
+			if config.SummaryHeader != "" && route.Operation.Summary != "" {
+				c.Response().Header().Set(config.SummaryHeader, route.Operation.Summary)
+			}
+
+			if len(config.AutoGenerateHeaders) > 0 {
+				generateMissingHeaders(req, route, config.AutoGenerateHeaders)
+			}
+
+			requestValidationInput := &openapi3filter.RequestValidationInput{
+				Request:    req,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:          !config.FailFast,
+					AuthenticationFunc:  config.AuthenticationFunc,
+					SkipSettingDefaults: config.SkipDefaults,
+				},
+			}
+
+			// streamed bodies aren't buffered, so they aren't stripped
+			streamed := !skipRequest && streamBodySchema(req, route, config.StreamBodyThreshold) != nil
+
+			switch config.ReadOnlyProperties {
+			case PropertyStrip:
+				if !streamed {
+					if err = stripReadOnlyProperties(req, route); err != nil {
+						if isBodyTooLarge(err) {
+							return bodyTooLarge()
+						}
+						return fmt.Errorf("failed reading request body: %v", err)
+					}
+				}
+				requestValidationInput.Options.ExcludeReadOnlyValidations = true
+			case PropertyIgnore:
+				requestValidationInput.Options.ExcludeReadOnlyValidations = true
+			}
+
+			if config.LenientOptionalBody && isOptionalBodyWithoutContentType(c.Request(), route) {
+				requestValidationInput.Options.ExcludeRequestBody = true
+				// the body isn't validated, so it isn't passed on either
+				for _, r := range []*http.Request{req, c.Request()} {
+					r.Body = http.NoBody
+					r.ContentLength = 0
+				}
+			}
+
+			optionalFields := config.OptionalOverrides[route.Operation.OperationID]
+			if config.TenantConfigResolver != nil {
+				if ro := config.TenantConfigResolver(c); ro != nil {
+					ro.apply(requestValidationInput.Options)
+					optionalFields = append(slices.Clip(optionalFields), ro.OptionalFields...)
+				}
+			}
+
+			// what's excluded from validation, e.g. by a RouteOption, isn't
+			// checked by the middleware either
+			skipBody := skipRequest || requestValidationInput.Options.ExcludeRequestBody
+			skipQuery := skipRequest || requestValidationInput.Options.ExcludeRequestQueryParams
+
+			// all issues are collected when fields are overridden, so that
+			// with FailFast the first issue not overridden is returned
+			if len(optionalFields) > 0 {
+				requestValidationInput.Options.MultiError = true
+			}
+
+			if config.RejectUnknownQueryParams && !skipQuery {
+				if names := undeclaredQueryParams(req, route); len(names) > 0 {
+					issues := make(map[string][]string, len(names))
+					var details []FieldError
+					for _, name := range names {
+						msg := fmt.Sprintf("query parameter '%s' is not declared", name)
+						issues["query."+name] = []string{msg}
+						if config.ErrorDetail.fields() {
+							details = append(details, FieldError{Field: name, In: "query", Code: "undeclared", Message: msg})
+						}
+					}
+					return validationError(c, config, http.StatusBadRequest, "Request error", issues, details)
+				}
+			}
+
+			var stream *streamValidator
+			if !skipBody {
+				if schema := streamBodySchema(req, route, config.StreamBodyThreshold); schema != nil {
+					requestValidationInput.Options.ExcludeRequestBody = true
+					stream = newStreamValidator(req.Body, schema, config.FieldNameCase)
+					req.Body = stream
+					c.Request().Body = stream
+					defer stream.finish()
+				}
+			}
+
+			if config.MaxBodyProperties > 0 && !skipBody && stream == nil && strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), ApplicationJSON) {
+				b, err := readBody(req)
+				if err != nil {
+					if isBodyTooLarge(err) {
+						return bodyTooLarge()
+					}
+					return fmt.Errorf("failed reading request body: %v", err)
+				}
+				if exceedsMaxProperties(b, config.MaxBodyProperties) {
+					msg := fmt.Sprintf("request body has an object with more than %d properties", config.MaxBodyProperties)
+					var details []FieldError
+					if config.ErrorDetail.fields() {
+						details = []FieldError{{In: "body", Code: "maxProperties", Message: msg}}
+					}
+					return validationError(c, config, http.StatusUnprocessableEntity, "Validation error", map[string][]string{"body": {msg}}, details)
+				}
+			}
+
+			var defaults map[string]any
+			if config.DefaultsContextKey != "" && !config.SkipDefaults {
+				defaults = missingParameterDefaults(req, route)
+			}
+
+			endSpan := startSpan(c.Request().Context(), config.Tracer, SpanValidateRequest, route)
+			start := time.Now()
+			if skipRequest {
+				err = validateSecurity(withEchoContext(ctx, c), requestValidationInput)
+			} else {
+				err = openapi3filter.ValidateRequest(withEchoContext(ctx, c), requestValidationInput)
+			}
+			if _, ok := err.(openapi3.MultiError); err != nil && !ok && config.FailFast {
+				err = openapi3.MultiError{err}
+			}
+			if req != c.Request() {
+				// the body was read, and restored, on the overridden request
+				c.Request().Body = req.Body
+			}
+			if d := time.Since(start); config.SoftTimeout > 0 && d > config.SoftTimeout && config.OnSlowValidation != nil {
+				config.OnSlowValidation(route.Operation.OperationID, d)
+			}
+			if me, ok := err.(openapi3.MultiError); ok {
+				me = withoutAbsentHeaderProperties(me, c.Request().Header)
+				if len(config.IgnoredHeaders) > 0 {
+					me = withoutIgnoredHeaders(me, config.IgnoredHeaders)
+				}
+				if len(optionalFields) > 0 {
+					me = withoutMissingFields(me, optionalFields)
+					if config.FailFast {
+						me = firstError(me)
+					}
+				}
+				if len(me) == 0 {
+					err = nil
+				} else {
+					err = me
+				}
+			}
+			endSpan(err)
+
+			if err != nil && isBodyTooLarge(err) {
+				return bodyTooLarge()
+			}
+
+			if me, ok := err.(openapi3.MultiError); ok {
+				if se := securityError(me); se != nil {
+					return securityFailure(c, config, se)
+				}
+			}
+
+			switch err := err.(type) {
+			case nil:
+			case openapi3.MultiError:
+				issues := convertError(err, config.FieldNameCase)
+
+				if config.DevMode {
+					if config.OnValidationError != nil {
+						config.OnValidationError(c, issues)
+					}
+					if isRequestBodyError(err) {
+						return c.String(statusCode(config, http.StatusBadRequest), formatDiagnostics("Request error", err, config.FieldNameCase))
+					}
+					return c.String(statusCode(config, http.StatusUnprocessableEntity), formatDiagnostics("Validation error", err, config.FieldNameCase))
+				}
+
+				var details []FieldError
+				if config.ErrorDetail.fields() {
+					details = collectFieldErrors(err, config.FieldNameCase)
+				}
+
+				if isRequestBodyError(err) {
+					val := issues["body"]
+					if isMissingBody(err) {
+						val = []string{config.MissingBodyMessage}
+						for i := range details {
+							if details[i].In == "body" && details[i].Code == "required" && details[i].Field == "" {
+								details[i].Message = config.MissingBodyMessage
+							}
+						}
+					} else if msg, ok := invalidJSONMessage(err); ok {
+						val = []string{msg}
+						for i := range details {
+							if details[i].In == "body" && details[i].Code == "invalid" && details[i].Field == "" {
+								details[i].Message = msg
+							}
+						}
+					}
+					if isUnsupportedMediaType(err, c.Request()) {
+						return validationError(c, config, http.StatusUnsupportedMediaType, "Unsupported media type", map[string][]string{"body": val}, details)
+					}
+					return validationError(c, config, http.StatusBadRequest, "Request error", map[string][]string{"body": val}, details)
+				}
+
+				return validationError(c, config, http.StatusUnprocessableEntity, "Validation error", issues, details)
+			default:
+				return err
+			}
+
+			if len(config.SchemaValidators) > 0 && !skipBody && stream == nil {
+				fes, err := validateSchemaRules(c.Request(), route, config)
+				if err != nil {
+					return fmt.Errorf("failed running schema validators: %v", err)
+				}
+				if len(fes) > 0 {
+					var details []FieldError
+					if config.ErrorDetail.fields() {
+						details = fes
+					}
+					return validationError(c, config, http.StatusUnprocessableEntity, "Validation error", fieldErrorIssues(fes), details)
+				}
+			}
+
+			c.Set(config.ContextKey, requestValidationInput)
+
+			if defaults != nil {
+				c.Set(config.DefaultsContextKey, defaults)
+			}
+
+			if config.TypedParams {
+				c.Set(ParamsContextKey, decodeParams(req, route, pathParams))
+			}
+
+			if config.OnValidated != nil {
+				config.OnValidated(c, requestValidationInput)
+			}
+
+			if status, ok := mockStatusRequested(c, route, config.MockMode, config.MockHeader); ok {
+				return mockResponse(c, route, status)
+			}
+
+			if config.ParseTimeFormats && !skipBody && stream == nil {
+				body, ok, err := parseTimeFormats(c.Request(), route)
+				if err != nil {
+					return fmt.Errorf("failed parsing time formats: %v", err)
+				}
+				if ok {
+					c.Set(config.TypedBodyContextKey, body)
+				}
+			}
+
+			var buffer *responseBuffer
+			if config.ValidateResponses && !skipResponse {
+				buffer = &responseBuffer{ResponseWriter: c.Response().Writer, header: c.Response().Header().Clone()}
+				c.Response().Writer = buffer
+			}
+
+			var capture *responseCapture
+			if config.AsyncResponseValidation && !skipResponse {
+				capture = &responseCapture{ResponseWriter: c.Response().Writer}
+				c.Response().Writer = capture
+			}
+
+			err = next(c)
+
+			tooLarge := err != nil && limit > 0 && isBodyTooLarge(err)
+			var streamIssues map[string][]string
+			if stream != nil {
+				streamIssues = stream.finish()
+			}
+			if (tooLarge || streamIssues != nil) && !c.Response().Committed {
+				if capture != nil {
+					c.Response().Writer = capture.ResponseWriter
+				}
+				if buffer != nil {
+					c.Response().Writer = buffer.ResponseWriter
+				}
+				if tooLarge {
+					return bodyTooLarge()
+				}
+				return validationError(c, config, http.StatusUnprocessableEntity, "Validation error", streamIssues, nil)
+			}
+
+			if capture != nil {
+				c.Response().Writer = capture.ResponseWriter
+				validateResponseAsync(ctx, c, config, requestValidationInput, capture)
+			}
+			if buffer != nil {
+				c.Response().Writer = buffer.ResponseWriter
+				endSpan := startSpan(c.Request().Context(), config.Tracer, SpanValidateResponse, route)
+				verr := validateBufferedResponse(ctx, c, requestValidationInput, buffer)
+				endSpan(verr)
+				if verr != nil {
+					config.Logger.Errorf("%s %s: %v", req.Method, route.Path, verr)
+					return echo.NewHTTPError(http.StatusInternalServerError, "Response validation error").SetInternal(verr)
+				}
+				if ferr := buffer.flush(); ferr != nil {
+					return ferr
+				}
+			}
+			if config.ValidateErrorResponses && !skipResponse {
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					if verr := validateErrorResponse(ctx, requestValidationInput, he); verr != nil {
+						return verr
+					}
+				}
+			}
+
+			return err
+		}
 	}
+}
 
-	if config.Schema == "" && len(config.SchemaBytes) == 0 {
-		panic("either schema or schemaBytes is required")
-	}
+// spec holds a loaded schema and the router built from it.
+type spec struct {
+	schema   *openapi3.T
+	router   routers.Router
+	routes   *routeCache
+	echo     *echoRouter
+	prefixes []string
 
-	if config.ContextKey == "" {
-		config.ContextKey = DefaultConfig.ContextKey
-	}
+	// hostless matches requests to allowedHosts on their path only
+	hostless     routers.Router
+	allowedHosts []string
+}
 
-	ctx := context.Background()
-	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+// loadSpec loads the schema from the source set in config and creates its
+// spec. The returned schemaSource is non-nil when the schema can be
+// reloaded, i.e. loaded from SchemaURL or from a watched file.
+func loadSpec(ctx context.Context, config Config) (*spec, schemaSource, error) {
+	loader := newLoader(ctx, config.LoaderOptions)
 
 	var schema *openapi3.T
+	var source schemaSource
 	var err error
 
-	if len(config.SchemaBytes) > 0 {
-		schema, err = loader.LoadFromData(config.SchemaBytes)
-	} else {
+	if config.Spec != nil {
+		schema = copySchemas(config.Spec)
+	} else if len(config.SchemaBytes) > 0 {
+		schema, err = loadFromData(loader, config.SchemaBytes, config.ConvertSwagger2)
+	} else if config.SchemaReader != nil {
+		var b []byte
+		if b, err = io.ReadAll(config.SchemaReader); err == nil {
+			schema, err = loadFromData(loader, b, config.ConvertSwagger2)
+		}
+	} else if config.Schema != "" && config.SchemaFS != nil {
+		if loader.ReadFromURIFunc == nil {
+			loader.ReadFromURIFunc = readFromFS(config.SchemaFS)
+		}
+		if config.ConvertSwagger2 {
+			convertSwagger2Reader(loader)
+		}
+		schema, err = loader.LoadFromFile(config.Schema)
+	} else if config.Schema != "" && config.WatchFile {
+		source = newFileSchema(config)
+		schema, err = source.load(ctx)
+	} else if config.Schema != "" {
+		if config.ConvertSwagger2 {
+			convertSwagger2Reader(loader)
+		}
 		schema, err = loader.LoadFromFile(config.Schema)
+	} else {
+		source = newRemoteSchema(config)
+		schema, err = source.load(ctx)
 	}
 
 	if err != nil {
-		panic(fmt.Sprintf("failed loading schema file: %v", err))
+		return nil, nil, fmt.Errorf("failed loading schema file: %v", err)
 	}
 
-	err = schema.Validate(ctx)
+	s, err := newSpec(ctx, config, schema)
 	if err != nil {
-		panic(fmt.Sprintf("failed validating schema: %v", err))
+		return nil, nil, err
 	}
 
-	router, err := gorillamux.NewRouter(schema)
-	if err != nil {
-		panic(fmt.Sprintf("failed creating router: %v", err))
+	return s, source, nil
+}
+
+// newLoader creates the loader used to load schemas, customized by options.
+func newLoader(ctx context.Context, options func(*openapi3.Loader)) *openapi3.Loader {
+	loader := &openapi3.Loader{Context: ctx, IsExternalRefsAllowed: true}
+	if options != nil {
+		options(loader)
 	}
+	return loader
+}
 
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			if config.Skipper(c) {
-				return next(c)
-			}
+// loadFromData loads the schema in data, converting it from Swagger 2.0 if
+// convert is set.
+func loadFromData(loader *openapi3.Loader, data []byte, convert bool) (*openapi3.T, error) {
+	if convert {
+		var err error
+		if data, err = convertSwagger2(data); err != nil {
+			return nil, err
+		}
+	}
+	return loader.LoadFromData(data)
+}
 
-			if check(c.Path(), c.Request().Method, config.ExemptRoutes) {
-				return next(c)
-			}
+// readFromFS returns an openapi3.ReadFromURIFunc reading files from fsys.
+func readFromFS(fsys fs.FS) openapi3.ReadFromURIFunc {
+	return func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		if location.Scheme != "" || location.Host != "" {
+			return nil, openapi3.ErrURINotSupported
+		}
+		return fs.ReadFile(fsys, path.Clean(location.Path))
+	}
+}
 
-			route, pathParams, err := router.FindRoute(c.Request())
-			if err != nil {
-				c.Logger().Debugf(
-					"error finding route for %s %s: %v",
-					c.Request().Method, c.Request().URL.String(), err,
-				)
+// newSpec validates schema and creates its router.
+func newSpec(ctx context.Context, config Config, schema *openapi3.T) (*spec, error) {
+	walkSchemas(schema, convertConst)
+	overrideAdditionalProperties(schema, config.AdditionalProperties)
 
-				if errors.Is(err, routers.ErrPathNotFound) {
-					return echo.NewHTTPError(http.StatusNotFound, "Path not found")
-				}
+	if len(config.SchemaValidators) > 0 {
+		if err := checkSchemaValidators(schema, config.SchemaValidators); err != nil {
+			return nil, err
+		}
+	}
 
-				if errors.Is(err, routers.ErrMethodNotAllowed) {
-					return echo.NewHTTPError(http.StatusMethodNotAllowed, "Method not allowed")
-				}
+	if config.ValidateExamples {
+		if err := validateExamples(schema); err != nil {
+			return nil, err
+		}
+	}
 
-				return err
-			}
+	err := schema.Validate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed validating schema: %v", err)
+	}
 
-			requestValidationInput := &openapi3filter.RequestValidationInput{
-				Request:    c.Request(),
-				PathParams: pathParams,
-				Route:      route,
-				Options: &openapi3filter.Options{
-					MultiError:         true,
-					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
-				},
-			}
-			err = openapi3filter.ValidateRequest(ctx, requestValidationInput)
-			switch err := err.(type) {
-			case nil:
-			case openapi3.MultiError:
-				issues := convertError(err)
-				names := make([]string, 0, len(issues))
+	if schema.Paths == nil || schema.Paths.Len() == 0 {
+		if !config.AllowEmptyPaths {
+			return nil, errors.New("schema defines no paths")
+		}
+		config.Logger.Warn("schema defines no paths, all requests will be rejected")
+	}
 
-				if val, ok := issues["body"]; ok {
-					return JSONValidationError(c, http.StatusBadRequest, "Request error", val)
-				}
+	var prefixes []string
+	if config.StripPathPrefix != "" {
+		prefixes = append(prefixes, config.StripPathPrefix)
+	}
+	if config.StripServerPrefixes {
+		prefixes = append(prefixes, serverPrefixes(schema)...)
+	}
 
-				for k := range issues {
-					names = append(names, k)
-				}
-				sort.Strings(names)
-				var errs []string
-				for _, k := range names {
-					msgs := issues[k]
-					for _, msg := range msgs {
-						errs = append(errs, msg)
-					}
-				}
-				return JSONValidationError(c, http.StatusUnprocessableEntity, "Validation error", errs)
-			default:
-				return err
-			}
+	// with prefixes stripped, requests are matched regardless of servers
+	doc := schema
+	if prefixes != nil {
+		doc = withoutServers(schema)
+	}
 
-			c.Set(config.ContextKey, requestValidationInput)
+	var router routers.Router
+	if config.RouterFunc != nil {
+		router, err = config.RouterFunc(doc)
+	} else {
+		router, err = newRouter(doc, config.IgnoreHost)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed creating router: %v", err)
+	}
+	if doc != schema {
+		router = &hostlessRouter{Router: router, doc: schema}
+	}
 
-			return next(c)
+	s := &spec{schema: schema, router: router, routes: newRouteCache(config.RouteCacheSize, schema), prefixes: prefixes}
+	if len(config.AllowedHosts) > 0 && !config.IgnoreHost && prefixes == nil && config.RouterFunc == nil {
+		s.hostless, err = newRouter(schema, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed creating router: %v", err)
 		}
+		s.allowedHosts = config.AllowedHosts
+	}
+	if config.Coverage != nil {
+		config.Coverage.setSpec(schema)
+	}
+	if config.UseEchoRouter {
+		s.echo = newEchoRouter(schema, prefixes)
 	}
+
+	return s, nil
+}
+
+func convertError(me openapi3.MultiError, fieldCase FieldNameCase) map[string][]string {
+	return convertErrorAt(me, fieldCase, nil)
 }
 
-func convertError(me openapi3.MultiError) map[string][]string {
+// convertErrorAt converts me, prefixing schema error paths with prefix.
+func convertErrorAt(me openapi3.MultiError, fieldCase FieldNameCase, prefix []string) map[string][]string {
 	issues := make(map[string][]string)
 	for _, err := range me {
 		switch err := err.(type) {
 		case *openapi3.SchemaError:
+			path := append(append([]string(nil), prefix...), err.JSONPointer()...)
+
+			// point to the originating constraint of composed schemas
+			if origin := schemaErrorOrigin(err); origin != nil {
+				for k, v := range convertErrorAt(origin, fieldCase, path) {
+					issues[k] = append(issues[k], v...)
+				}
+				continue
+			}
+
 			var field string
-			if path := err.JSONPointer(); len(path) > 0 {
-				field = strings.Join(path, ".")
+			if len(path) > 0 {
+				field = strings.Join(fieldCase.apply(path), ".")
 			}
 
 			var msg string
 			if len(field) > 0 {
-				msg = fmt.Sprintf("%s: %s", field, err.Reason)
+				msg = fmt.Sprintf("%s: %s", field, schemaErrorReason(err))
 			} else {
-				msg = fmt.Sprintf("%s", err.Reason)
+				msg = schemaErrorReason(err)
 			}
 
 			msg = strings.ReplaceAll(msg, "\"", "'")
@@ -196,16 +1442,14 @@ func convertError(me openapi3.MultiError) map[string][]string {
 			if err.Parameter != nil {
 				prefix := err.Parameter.In
 				name := fmt.Sprintf("%s.%s", prefix, err.Parameter.Name)
-				split := strings.Split(err.Err.Error(), "\n")
-
-				msg := fmt.Sprintf("parameter '%s' in %s has an error: %s", err.Parameter.Name, prefix, split[0])
+				msg := parameterErrorMessage(err.Parameter, err.Err)
 
 				issues[name] = append(issues[name], msg)
 				continue
 			}
 
-			if err, ok := err.Err.(openapi3.MultiError); ok {
-				for k, v := range convertError(err) {
+			if me := asMultiError(err.Err); me != nil {
+				for k, v := range convertErrorAt(me, fieldCase, prefix) {
 					issues[k] = append(issues[k], v...)
 				}
 				continue
@@ -224,30 +1468,460 @@ func convertError(me openapi3.MultiError) map[string][]string {
 	return issues
 }
 
+// schemaErrorReason returns the reason of err, naming the expected value of
+// single value enums, e.g. from "const", and the expected format.
+func schemaErrorReason(err *openapi3.SchemaError) string {
+	if err.SchemaField == "format" && err.Schema != nil {
+		return fmt.Sprintf("value is not a valid %s", err.Schema.Format)
+	}
+	if err.SchemaField == "enum" && err.Schema != nil && len(err.Schema.Enum) == 1 {
+		if b, e := json.Marshal(err.Schema.Enum[0]); e == nil {
+			return fmt.Sprintf("value must be %s", b)
+		}
+	}
+	return err.Reason
+}
+
+// schemaErrorOrigin returns the errors that caused err when err reports a
+// failed allOf, or nil.
+func schemaErrorOrigin(err *openapi3.SchemaError) openapi3.MultiError {
+	if err.SchemaField != "allOf" {
+		return nil
+	}
+	return asMultiError(err.Origin)
+}
+
+// asMultiError returns err as a MultiError if it's a MultiError or a
+// SchemaError, or nil.
+func asMultiError(err error) openapi3.MultiError {
+	switch err := err.(type) {
+	case openapi3.MultiError:
+		return err
+	case *openapi3.SchemaError:
+		return openapi3.MultiError{err}
+	}
+	return nil
+}
+
+// parameterErrorMessage returns the message describing why param failed
+// validation with err.
+func parameterErrorMessage(param *openapi3.Parameter, err error) string {
+	var se *openapi3.SchemaError
+	if errors.As(err, &se) && se.SchemaField == "uniqueItems" && len(se.JSONPointer()) == 0 {
+		return fmt.Sprintf("parameter '%s' in %s must contain unique items", param.Name, param.In)
+	}
+
+	msg := fmt.Sprintf("parameter '%s' in %s has an error: %s", param.Name, param.In, parameterErrorReason(err))
+	return strings.ReplaceAll(msg, "\"", "'")
+}
+
+// parameterErrorReason returns a single line describing why a parameter
+// failed validation.
+func parameterErrorReason(err error) string {
+	var pe *openapi3filter.ParseError
+	if errors.As(err, &pe) {
+		// items of arrays and properties of objects are wrapped with their path
+		path := pe.Path()
+		for pe.Value == nil {
+			cause, ok := pe.Cause.(*openapi3filter.ParseError)
+			if !ok {
+				break
+			}
+			pe = cause
+		}
+
+		if pe.Kind == openapi3filter.KindInvalidFormat && pe.Value != nil {
+			reason := fmt.Sprintf("value '%v' is %s", pe.Value, pe.Reason)
+			if len(path) > 0 {
+				elems := make([]string, len(path))
+				for i, v := range path {
+					elems[i] = fmt.Sprint(v)
+				}
+				reason = fmt.Sprintf("%s: %s", strings.Join(elems, "."), reason)
+			}
+			return reason
+		}
+	}
+
+	// content-encoded parameters are validated as a whole document
+	var se *openapi3.SchemaError
+	if errors.As(err, &se) && se.Origin == nil {
+		if path := se.JSONPointer(); len(path) > 0 {
+			return fmt.Sprintf("%s: %s", strings.Join(path, "."), se.Reason)
+		}
+	}
+
+	split := strings.Split(err.Error(), "\n")
+	return split[0]
+}
+
+// HeaderIdempotencyKey is the header carrying the idempotency key of a request.
+const HeaderIdempotencyKey = "Idempotency-Key"
+
+// IdempotencyKeySkipper returns a middleware.Skipper that skips requests whose
+// Idempotency-Key header is set and reported as already seen by seen.
+func IdempotencyKeySkipper(seen func(key string) bool) middleware.Skipper {
+	return func(c echo.Context) bool {
+		key := c.Request().Header.Get(HeaderIdempotencyKey)
+		if key == "" {
+			return false
+		}
+		return seen(key)
+	}
+}
+
+// withMethodOverride returns a shallow copy of req using the method of its
+// X-HTTP-Method-Override header, or req if it has none.
+func withMethodOverride(req *http.Request) *http.Request {
+	method := req.Header.Get(echo.HeaderXHTTPMethodOverride)
+	if method == "" {
+		return req
+	}
+
+	r := req.WithContext(req.Context())
+	r.Method = strings.ToUpper(method)
+	return r
+}
+
+// isOptionalBodyWithoutContentType reports whether req carries a body without
+// a Content-Type header for an operation that doesn't require a body.
+func isOptionalBodyWithoutContentType(req *http.Request, route *routers.Route) bool {
+	requestBody := route.Operation.RequestBody
+	if requestBody == nil || requestBody.Value == nil || requestBody.Value.Required {
+		return false
+	}
+
+	if req.Header.Get(echo.HeaderContentType) != "" {
+		return false
+	}
+
+	return req.Body != nil && req.Body != http.NoBody && req.ContentLength != 0
+}
+
+// exceedsMaxProperties reports whether any object of the JSON document b has
+// more than max properties. It reads b token by token to avoid decoding
+// large objects. Invalid documents are left to validation.
+func exceedsMaxProperties(b []byte, max int) bool {
+	type object struct {
+		properties int
+		key        bool
+	}
+
+	// nil entries are arrays
+	var stack []*object
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return false
+		}
+
+		var top *object
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			stack = append(stack, &object{key: true})
+		case json.Delim('['):
+			stack = append(stack, nil)
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			if len(stack) > 0 && stack[len(stack)-1] != nil {
+				stack[len(stack)-1].key = true
+			}
+		default:
+			if top == nil {
+				continue
+			}
+			if top.key {
+				top.properties++
+				if top.properties > max {
+					return true
+				}
+			}
+			top.key = !top.key
+		}
+	}
+}
+
+// validateErrorResponse validates the body echo's default HTTPErrorHandler
+// would send for he against the operation's response for he.Code.
+func validateErrorResponse(ctx context.Context, input *openapi3filter.RequestValidationInput, he *echo.HTTPError) error {
+	if herr, ok := he.Internal.(*echo.HTTPError); ok {
+		he = herr
+	}
+
+	var v any = he.Message
+	switch m := he.Message.(type) {
+	case string:
+		v = echo.Map{"message": m}
+	case json.Marshaler:
+	case error:
+		v = echo.Map{"message": m.Error()}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed marshaling error response: %v", err)
+	}
+
+	header := http.Header{}
+	header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 he.Code,
+		Header:                 header,
+		Options: &openapi3filter.Options{
+			MultiError: true,
+		},
+	}
+	responseValidationInput.SetBodyBytes(b)
+
+	return validateResponse(ctx, responseValidationInput)
+}
+
+// withoutMissingFields returns me without the "required" errors of fields.
+func withoutMissingFields(me openapi3.MultiError, fields []string) openapi3.MultiError {
+	var res openapi3.MultiError
+	for _, err := range me {
+		switch e := err.(type) {
+		case *openapi3.SchemaError:
+			if e.SchemaField == "required" && slices.Contains(fields, strings.Join(e.JSONPointer(), ".")) {
+				continue
+			}
+		case *openapi3filter.RequestError:
+			if inner, ok := e.Err.(openapi3.MultiError); ok && e.RequestBody != nil {
+				inner = withoutMissingFields(inner, fields)
+				if len(inner) == 0 {
+					continue
+				}
+				filtered := *e
+				filtered.Err = inner
+				err = &filtered
+			}
+		}
+		res = append(res, err)
+	}
+	return res
+}
+
+// firstError returns the first error of me, keeping only the first error of
+// the request body errors it wraps.
+func firstError(me openapi3.MultiError) openapi3.MultiError {
+	if len(me) == 0 {
+		return me
+	}
+
+	err := me[0]
+	if re, ok := err.(*openapi3filter.RequestError); ok {
+		if inner, ok := re.Err.(openapi3.MultiError); ok && len(inner) > 1 {
+			first := *re
+			first.Err = inner[:1]
+			err = &first
+		}
+	}
+
+	return openapi3.MultiError{err}
+}
+
+// isRequestBodyError reports whether me contains an error of the request
+// body as a whole, e.g. a missing or undecodable body, rather than of its
+// fields.
+func isRequestBodyError(me openapi3.MultiError) bool {
+	for _, err := range me {
+		var re *openapi3filter.RequestError
+		if errors.As(err, &re) && re.RequestBody != nil && asMultiError(re.Err) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// prefixUnsupportedMediaType is the reason openapi3filter gives for request
+// bodies of a media type the operation doesn't declare.
+const prefixUnsupportedMediaType = "header Content-Type has unexpected value"
+
+// isUnsupportedMediaType reports whether me contains a request body of an
+// undeclared media type. Bodies without a Content-Type aren't included.
+func isUnsupportedMediaType(me openapi3.MultiError, req *http.Request) bool {
+	if req.Header.Get(echo.HeaderContentType) == "" {
+		return false
+	}
+
+	for _, err := range me {
+		var re *openapi3filter.RequestError
+		if errors.As(err, &re) && re.RequestBody != nil && strings.HasPrefix(re.Reason, prefixUnsupportedMediaType) {
+			return true
+		}
+	}
+	return false
+}
+
+// invalidJSONMessage returns a message describing the syntax error of a
+// request body declared as JSON, if me contains one.
+func invalidJSONMessage(me openapi3.MultiError) (string, bool) {
+	for _, err := range me {
+		var re *openapi3filter.RequestError
+		if !errors.As(err, &re) || re.RequestBody == nil {
+			continue
+		}
+
+		var se *json.SyntaxError
+		if errors.As(re.Err, &se) {
+			return fmt.Sprintf("request body is not valid JSON: %v", se), true
+		}
+		if errors.Is(re.Err, io.ErrUnexpectedEOF) {
+			return "request body is not valid JSON: unexpected end of input", true
+		}
+	}
+	return "", false
+}
+
+// isMissingBody reports whether me contains a missing required request body.
+func isMissingBody(me openapi3.MultiError) bool {
+	for _, err := range me {
+		var re *openapi3filter.RequestError
+		if errors.As(err, &re) && re.RequestBody != nil && errors.Is(re.Err, openapi3filter.ErrInvalidRequired) {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenIssues returns the messages of issues sorted by field.
+func flattenIssues(issues map[string][]string) []string {
+	names := make([]string, 0, len(issues))
+	for k := range issues {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var errs []string
+	for _, k := range names {
+		errs = append(errs, issues[k]...)
+	}
+	return errs
+}
+
 func check(path string, method string, m map[string][]string) bool {
 	for k, v := range m {
-		if k == path {
-			for _, i := range v {
-				if method == i {
+		if !matchRoute(k, path) {
+			continue
+		}
+		for _, i := range v {
+			if method == i || i == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchRoute reports whether the echo path p matches pattern, where "*"
+// matches within a segment and "**" matches any number of segments.
+func matchRoute(pattern string, p string) bool {
+	if pattern == p {
+		return true
+	}
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(p, "/"))
+}
+
+// matchSegments reports whether the path segments segs match the pattern
+// segments pattern.
+func matchSegments(pattern []string, segs []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segs); i++ {
+				if matchSegments(pattern[1:], segs[i:]) {
 					return true
 				}
 			}
+			return false
+		}
+
+		if len(segs) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segs[0]); !ok {
+			return false
 		}
+		pattern, segs = pattern[1:], segs[1:]
 	}
-	return false
+	return len(segs) == 0
 }
 
 type ValidationError struct {
 	echo.HTTPError
-	Errors []string `json:"errors,omitempty"`
+	Errors  []string     `json:"errors,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+	TraceID string       `json:"traceId,omitempty"`
 }
 
 func JSONValidationError(c echo.Context, status int, msg string, errors []string) error {
 	return c.JSON(status, ValidationError{
-		echo.HTTPError{
+		HTTPError: echo.HTTPError{
 			Code:    status,
 			Message: msg,
 		},
-		errors,
+		Errors: errors,
 	})
 }
+
+// statusCode returns the status responded for failures with status.
+func statusCode(config Config, status int) int {
+	if override, ok := config.StatusCodes[status]; ok {
+		return override
+	}
+	return status
+}
+
+// validationError writes a ValidationError response for issues according
+// to config.
+func validationError(c echo.Context, config Config, status int, msg string, issues map[string][]string, details []FieldError) error {
+	status = statusCode(config, status)
+
+	if config.OnValidationError != nil {
+		config.OnValidationError(c, issues)
+	}
+
+	if config.ErrorHandler != nil {
+		return config.ErrorHandler(c, status, issues)
+	}
+
+	errors := flattenIssues(issues)
+	if config.ErrorDetail == ErrorDetailStructured && len(details) > 0 {
+		errors = nil
+	}
+	ve := ValidationError{
+		HTTPError: echo.HTTPError{
+			Code:    status,
+			Message: msg,
+		},
+		Errors:  errors,
+		Details: details,
+	}
+
+	if config.TraceIDExtractor != nil {
+		ve.TraceID = config.TraceIDExtractor(c)
+	}
+
+	if config.UseEchoErrorFormat {
+		m := echo.Map{"message": msg}
+		if len(errors) > 0 {
+			m["errors"] = errors
+		}
+		if len(ve.Details) > 0 {
+			m["details"] = ve.Details
+		}
+		if ve.TraceID != "" {
+			m["traceId"] = ve.TraceID
+		}
+		return echo.NewHTTPError(status, m)
+	}
+
+	return c.JSON(status, ve)
+}
@@ -2,13 +2,23 @@ package openapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -20,6 +30,7 @@ func TestOpenAPIWithConfig_Schema_Load_Panics(t *testing.T) {
 		{"no schema", Config{}},
 		{"invalid schema", Config{Schema: "./fixtures/invalid.yaml"}},
 		{"invalid path", Config{Schema: "/invalid/path"}},
+		{"empty paths", Config{Schema: "./fixtures/empty.yaml"}},
 	}
 
 	for _, tc := range testCases {
@@ -30,6 +41,34 @@ func TestOpenAPIWithConfig_Schema_Load_Panics(t *testing.T) {
 	}
 }
 
+func TestOpenAPIWithConfig_AllowEmptyPaths(t *testing.T) {
+	e := echo.New()
+
+	buf := new(bytes.Buffer)
+	logger := log.New("test")
+	logger.SetOutput(buf)
+
+	e.GET("/", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	assert.NotPanics(t, func() {
+		e.Use(OpenAPIWithConfig(Config{
+			Schema:          "./fixtures/empty.yaml",
+			AllowEmptyPaths: true,
+			Logger:          logger,
+		}))
+	})
+	assert.Contains(t, buf.String(), "schema defines no paths")
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+}
+
 func TestOpenAPIWithConfig_Skipper(t *testing.T) {
 	e := echo.New()
 
@@ -89,6 +128,124 @@ func TestOpenAPIWithConfig_ExemptRoutes(t *testing.T) {
 	assert.Equal(t, http.StatusOK, resp.Code)
 }
 
+func TestOpenAPIWithConfig_ExemptRoutes_Patterns(t *testing.T) {
+	e := echo.New()
+
+	for _, p := range []string{"/healthz", "/internal/status", "/internal/jobs/:id", "/debug", "/debug/pprof/heap"} {
+		e.Any(p, func(c echo.Context) error {
+			return c.JSON(http.StatusOK, "ok")
+		})
+	}
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+		ExemptRoutes: map[string][]string{
+			"/healthz":    {http.MethodGet},
+			"/internal/*": {"*"},
+			"/debug/**":   {http.MethodGet},
+		},
+	}))
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+	}{
+		{"exact", http.MethodGet, "/healthz", http.StatusOK},
+		{"exact other method", http.MethodPost, "/healthz", http.StatusNotFound},
+		{"single segment", http.MethodGet, "/internal/status", http.StatusOK},
+		{"single segment any method", http.MethodDelete, "/internal/status", http.StatusOK},
+		{"single segment too deep", http.MethodGet, "/internal/jobs/1", http.StatusNotFound},
+		{"any segments none", http.MethodGet, "/debug", http.StatusOK},
+		{"any segments many", http.MethodGet, "/debug/pprof/heap", http.StatusOK},
+		{"any segments other method", http.MethodPost, "/debug/pprof/heap", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_ExemptOperations(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+	e.DELETE("/orders/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:           "./fixtures/openapi.yaml",
+		ExemptOperations: []string{"getOrder"},
+	}))
+
+	testCases := []struct {
+		name       string
+		method     string
+		statusCode int
+	}{
+		{"exempt", http.MethodGet, http.StatusOK},
+		{"not exempt", http.MethodDelete, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/orders/abc", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_ExemptTags(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+	e.DELETE("/orders/:id", func(c echo.Context) error {
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:     "./fixtures/openapi.yaml",
+		ExemptTags: []string{"internal"},
+	}))
+
+	testCases := []struct {
+		name       string
+		method     string
+		statusCode int
+	}{
+		{"exempt", http.MethodGet, http.StatusOK},
+		{"not exempt", http.MethodDelete, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/orders/abc", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
 func TestOpenAPIWithConfig_FindRoute(t *testing.T) {
 	testCases := []struct {
 		name       string
@@ -313,3 +470,1701 @@ paths:
 		})
 	}
 }
+
+func TestOpenAPIWithConfig_ValidateErrorResponses(t *testing.T) {
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"valid error body", echo.NewHTTPError(http.StatusNotFound, "Not found"), http.StatusNotFound},
+		{"invalid error body", echo.NewHTTPError(http.StatusNotFound, echo.Map{"error": "Not found"}), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/error", func(c echo.Context) error {
+				return tc.err
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:                 "./fixtures/openapi.yaml",
+				ValidateErrorResponses: true,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/error", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_LenientOptionalBody(t *testing.T) {
+	testCases := []struct {
+		name       string
+		lenient    bool
+		statusCode int
+	}{
+		{"strict", false, http.StatusBadRequest},
+		{"lenient", true, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var received []byte
+			e.POST("/optional-body", func(c echo.Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				received = b
+				assert.Equal(t, int64(0), c.Request().ContentLength)
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:              "./fixtures/openapi.yaml",
+				LenientOptionalBody: tc.lenient,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/optional-body", bytes.NewBuffer([]byte(`{"username": "test"}`)))
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Empty(t, received)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Path_Pattern(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+		errors     []string
+	}{
+		{"matching", "/patterned/ABC", http.StatusOK, nil},
+		{
+			"not matching",
+			"/patterned/abcd",
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'code' in path has an error: string doesn't match the regular expression '^[A-Z]{3}$'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/patterned/:code", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_AdditionalProperties_Schema(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"conforming", `{"name": "test", "count": 1}`, http.StatusOK, nil},
+		{
+			"non-conforming",
+			`{"name": "test", "count": "one"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"count: value must be an integer"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/metadata", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/metadata", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_IdempotencySkipper(t *testing.T) {
+	testCases := []struct {
+		name       string
+		key        string
+		statusCode int
+	}{
+		{"no key", "", http.StatusBadRequest},
+		{"new key", "new", http.StatusBadRequest},
+		{"replayed key", "replayed", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				IdempotencySkipper: IdempotencyKeySkipper(func(key string) bool {
+					return key == "replayed"
+				}),
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/validation", nil)
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if tc.key != "" {
+				req.Header.Set(HeaderIdempotencyKey, tc.key)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Path_Integer(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+		errors     []string
+	}{
+		{"integer", "/orders/1", http.StatusOK, nil},
+		{
+			"not an integer",
+			"/orders/abc",
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'id' in path has an error: value 'abc' is an invalid integer"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/orders/:id", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Same_Name_Path_And_Query(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", "/items/abc?name=ab", http.StatusOK, nil},
+		{
+			"path error",
+			"/items/ab?name=ab",
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'name' in path has an error: minimum string length is 3"},
+		},
+		{
+			"query error",
+			"/items/abc?name=abc",
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'name' in query has an error: maximum string length is 2"},
+		},
+		{
+			"path and query errors",
+			"/items/ab?name=abc",
+			http.StatusUnprocessableEntity,
+			[]string{
+				"parameter 'name' in path has an error: minimum string length is 3",
+				"parameter 'name' in query has an error: maximum string length is 2",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/items/:name", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_OnValidated(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		operationID string
+	}{
+		{"valid", "/orders/1", "getOrder"},
+		{"invalid", "/orders/abc", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/orders/:id", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			var operationID string
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				OnValidated: func(c echo.Context, input *openapi3filter.RequestValidationInput) {
+					operationID = input.Route.Operation.OperationID
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.operationID, operationID)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_TraceIDExtractor(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+		TraceIDExtractor: func(c echo.Context) string {
+			return c.Request().Header.Get("X-Trace-Id")
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/abc", nil)
+	req.Header.Set("X-Trace-Id", "4bf92f3577b34da6a3ce929d0e0e4736")
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", j.TraceID)
+}
+
+func TestOpenAPIWithConfig_Nullable(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"null", `{"nickname": null}`, http.StatusOK, nil},
+		{"string", `{"nickname": "test"}`, http.StatusOK, nil},
+		{"wrong type", `{"nickname": 1}`, http.StatusUnprocessableEntity, []string{"nickname: value must be a string"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/profiles", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/profiles", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_MissingBodyMessage(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/validation", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:             "./fixtures/openapi.yaml",
+		MissingBodyMessage: "Request body is required",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Equal(t, []string{"Request body is required"}, j.Errors)
+}
+
+func TestOpenAPIWithConfig_SummaryHeader(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:        "./fixtures/openapi.yaml",
+		SummaryHeader: "X-Operation-Summary",
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "Get an order", resp.Header().Get("X-Operation-Summary"))
+}
+
+func TestOpenAPIWithConfig_Query_Content(t *testing.T) {
+	testCases := []struct {
+		name       string
+		filter     string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"status": "open"}`, http.StatusOK, nil},
+		{
+			"invalid value",
+			`{"status": "pending"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'filter' in query has an error: status: value is not one of the allowed values ['open','closed']"},
+		},
+		{
+			"invalid encoding",
+			`{"status"`,
+			http.StatusUnprocessableEntity,
+			[]string{"parameter 'filter' in query has an error: error unmarshaling parameter 'filter'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/search", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/search?filter="+url.QueryEscape(tc.filter), nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_OptionalOverrides(t *testing.T) {
+	testCases := []struct {
+		name       string
+		overrides  map[string][]string
+		failFast   bool
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"no override", nil, false, `{}`, http.StatusUnprocessableEntity, []string{"username: property 'username' is missing"}},
+		{"override", map[string][]string{"createValidation": {"username"}}, false, `{}`, http.StatusOK, nil},
+		{"override fail fast", map[string][]string{"createValidation": {"username"}}, true, `{}`, http.StatusOK, nil},
+		{
+			"override fail fast keeps first other error",
+			map[string][]string{"createValidation": {"username"}},
+			true,
+			`{"invalid": "value"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"property 'invalid' is unsupported"},
+		},
+		{
+			"override keeps other errors",
+			map[string][]string{"createValidation": {"username"}},
+			false,
+			`{"invalid": "value"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"property 'invalid' is unsupported"},
+		},
+		{
+			"override of other operation",
+			map[string][]string{"getOrder": {"username"}},
+			false,
+			`{}`,
+			http.StatusUnprocessableEntity,
+			[]string{"username: property 'username' is missing"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:            "./fixtures/openapi.yaml",
+				OptionalOverrides: tc.overrides,
+				FailFast:          tc.failFast,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Nested_AllOf(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"id": "abc", "name": "test", "role": "engineer"}`, http.StatusOK, nil},
+		{
+			"grandparent constraint",
+			`{"id": "a", "name": "test", "role": "engineer"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"id: minimum string length is 3"},
+		},
+		{
+			"grandparent required",
+			`{"name": "test", "role": "engineer"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"id: property 'id' is missing"},
+		},
+		{
+			"parent required",
+			`{"id": "abc", "role": "engineer"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"name: property 'name' is missing"},
+		},
+		{
+			"child constraint",
+			`{"id": "abc", "name": "test", "role": "ceo"}`,
+			http.StatusUnprocessableEntity,
+			[]string{"role: value is not one of the allowed values ['engineer','manager']"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/employees", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/employees", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_CatchAllHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+		body       string
+	}{
+		{"matched", "/orders/1", http.StatusOK, "\"ok\"\n"},
+		{"extra segments", "/orders/1/extra", http.StatusTeapot, "catch-all"},
+		{"unknown", "/unknown", http.StatusTeapot, "catch-all"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/*", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				CatchAllHandler: func(c echo.Context) error {
+					return c.String(http.StatusTeapot, "catch-all")
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.body, resp.Body.String())
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Const(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"constant", `{"type": "subscription"}`, http.StatusOK, nil},
+		{"other value", `{"type": "order"}`, http.StatusUnprocessableEntity, []string{"type: value must be 'subscription'"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/subscriptions", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/subscriptions", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_SoftTimeout(t *testing.T) {
+	testCases := []struct {
+		name        string
+		softTimeout time.Duration
+		slow        bool
+	}{
+		{"fast", time.Hour, false},
+		{"slow", time.Nanosecond, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/orders/:id", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			var operationID string
+			var duration time.Duration
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:      "./fixtures/openapi.yaml",
+				SoftTimeout: tc.softTimeout,
+				OnSlowValidation: func(id string, d time.Duration) {
+					operationID = id
+					duration = d
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/orders/1", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusOK, resp.Code)
+			if tc.slow {
+				assert.Equal(t, "getOrder", operationID)
+				assert.Greater(t, duration, tc.softTimeout)
+			} else {
+				assert.Empty(t, operationID)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Recursive_Schema(t *testing.T) {
+	nested := func(depth int, leaf string) string {
+		body := leaf
+		for i := 0; i < depth; i++ {
+			body = fmt.Sprintf(`{"name": "node%d", "children": [%s]}`, i, body)
+		}
+		return body
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"leaf", `{"name": "root"}`, http.StatusOK, nil},
+		{"nested tree", nested(20, `{"name": "leaf"}`), http.StatusOK, nil},
+		{"invalid nested node", nested(3, `{"name": ""}`), http.StatusUnprocessableEntity, []string{"children.0.children.0.children.0.name: minimum string length is 1"}},
+		{"missing nested name", nested(2, `{"children": []}`), http.StatusUnprocessableEntity, []string{"children.0.children.0.name: property 'name' is missing"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/trees", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/trees", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_UseEchoErrorFormat(t *testing.T) {
+	e := echo.New()
+
+	var handled error
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled = err
+		e.DefaultHTTPErrorHandler(err, c)
+	}
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:             "./fixtures/openapi.yaml",
+		UseEchoErrorFormat: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/abc", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	var he *echo.HTTPError
+	assert.ErrorAs(t, handled, &he)
+	assert.Equal(t, http.StatusUnprocessableEntity, he.Code)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	assert.JSONEq(t, `{"message":"Validation error","errors":["parameter 'id' in path has an error: value 'abc' is an invalid integer"]}`, resp.Body.String())
+}
+
+func TestOpenAPIWithConfig_Query_UniqueItems(t *testing.T) {
+	testCases := []struct {
+		name       string
+		query      string
+		statusCode int
+		errors     []string
+	}{
+		{"unique", "tags=a&tags=b", http.StatusOK, nil},
+		{"duplicates", "tags=a&tags=a", http.StatusUnprocessableEntity, []string{"parameter 'tags' in query must contain unique items"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/tags", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/tags?"+tc.query, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_MaxBodyProperties(t *testing.T) {
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"within limit", "/metadata", `{"name": "test", "a": 1, "b": 2}`, http.StatusOK, nil},
+		{"exceeding limit", "/metadata", `{"name": "test", "a": 1, "b": 2, "c": 3}`, http.StatusUnprocessableEntity, []string{"request body has an object with more than 3 properties"}},
+		{"nested within limit", "/trees", `{"name": "root", "children": [{"name": "a", "b": 1, "c": 2}]}`, http.StatusOK, nil},
+		{"nested exceeding limit", "/trees", `{"name": "root", "children": [{"name": "a", "b": 1, "c": 2, "d": {}}]}`, http.StatusUnprocessableEntity, []string{"request body has an object with more than 3 properties"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST(tc.path, func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:            "./fixtures/openapi.yaml",
+				MaxBodyProperties: 3,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestExceedsMaxProperties(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected bool
+	}{
+		{"empty object", `{}`, false},
+		{"at limit", `{"a": 1, "b": "2"}`, false},
+		{"over limit", `{"a": 1, "b": 2, "c": 3}`, true},
+		{"object values", `{"a": {"x": 1}, "b": [1, 2, 3, 4]}`, false},
+		{"nested over limit", `{"a": {"x": 1, "y": 2, "z": 3}}`, true},
+		{"in array", `[{"a": 1}, {"a": 1, "b": 2, "c": null}]`, true},
+		{"invalid", `{"a": 1,`, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, exceedsMaxProperties([]byte(tc.body), 2))
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Path_Dots(t *testing.T) {
+	testCases := []struct {
+		name       string
+		filename   string
+		statusCode int
+	}{
+		{"extension", "report.pdf", http.StatusOK},
+		{"dots", "report.2024.pdf", http.StatusOK},
+		{"special characters", "q1_report-final.2024.pdf", http.StatusOK},
+		{"other extension", "report.2024.txt", http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var filename string
+			e.GET("/files/:filename", func(c echo.Context) error {
+				input := c.Get("validator").(*openapi3filter.RequestValidationInput)
+				filename = input.PathParams["filename"]
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodGet, "/files/"+tc.filename, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.statusCode == http.StatusOK {
+				assert.Equal(t, tc.filename, filename)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Request_Media_Types(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		statusCode  int
+		errors      []string
+	}{
+		{"json", echo.MIMEApplicationJSON, `{"title": "report"}`, http.StatusOK, nil},
+		{"invalid json", echo.MIMEApplicationJSON, `{"title": "a"}`, http.StatusUnprocessableEntity, []string{"title: minimum string length is 3"}},
+		{"form", echo.MIMEApplicationForm, "name=report", http.StatusOK, nil},
+		{"invalid form", echo.MIMEApplicationForm, "name=a", http.StatusUnprocessableEntity, []string{"name: minimum string length is 3"}},
+		{"json with form schema", echo.MIMEApplicationJSON, `{"name": "report"}`, http.StatusUnprocessableEntity, []string{"title: property 'title' is missing"}},
+		{
+			"undeclared",
+			echo.MIMETextPlain,
+			"report",
+			http.StatusUnsupportedMediaType,
+			[]string{`request body has an error: header Content-Type has unexpected value "text/plain"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/documents", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/documents", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, tc.contentType)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Invalid_JSON(t *testing.T) {
+	testCases := []struct {
+		name   string
+		body   string
+		errors []string
+	}{
+		{"plain text", "hello", []string{"request body is not valid JSON: invalid character 'h' looking for beginning of value"}},
+		{"truncated", `{"title": "report"`, []string{"request body is not valid JSON: unexpected end of input"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/documents", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:      "./fixtures/openapi.yaml",
+				ErrorDetail: ErrorDetailFull,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/documents", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+			j := &ValidationError{}
+			err := json.Unmarshal(resp.Body.Bytes(), j)
+			assert.NoError(t, err)
+			assert.Equal(t, "Request error", j.Message)
+			assert.Equal(t, tc.errors, j.Errors)
+			assert.Equal(t, []FieldError{{In: "body", Code: "invalid", Message: tc.errors[0]}}, j.Details)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_Referenced_Request_Body(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"body": "hello", "author": {"name": "test"}}`, http.StatusOK, nil},
+		{"missing body", "", http.StatusBadRequest, []string{"request body has an error: value is required but missing"}},
+		{"missing field", `{"author": {"name": "test"}}`, http.StatusUnprocessableEntity, []string{"body: property 'body' is missing"}},
+		{"invalid nested field", `{"body": "hello", "author": {"name": "a very long name"}}`, http.StatusUnprocessableEntity, []string{"author.name: maximum string length is 10"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/comments", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/comments", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_GraphQL_Envelope(t *testing.T) {
+	query := `query { user(id: 1) { ` + strings.Repeat("name email ", 100000) + `} }`
+	large, err := json.Marshal(map[string]any{"query": query, "variables": map[string]any{"id": 1}})
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"query": "{ user { name } }", "operationName": null, "variables": {"id": 1}}`, http.StatusOK, nil},
+		{"large query", string(large), http.StatusOK, nil},
+		{"missing query", `{"variables": {"id": 1}}`, http.StatusUnprocessableEntity, []string{"query: property 'query' is missing"}},
+		{"invalid variables", `{"query": "{ user { name } }", "variables": [1]}`, http.StatusUnprocessableEntity, []string{"variables: value must be an object"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/graphql", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_LoaderOptions(t *testing.T) {
+	schema := []byte(`
+openapi: 3.0.4
+info:
+  version: 1.0.0
+  title: Refs API
+paths:
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: 'mem://schemas/user.yaml#/User'
+      responses:
+        '200':
+          description: Successful response
+`)
+
+	refs := map[string][]byte{
+		"mem://schemas/user.yaml": []byte(`
+User:
+  type: object
+  required:
+    - username
+  properties:
+    username:
+      type: string
+`),
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+	}{
+		{"valid", `{"username": "test"}`, http.StatusOK},
+		{"invalid", `{}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/users", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				SchemaBytes: schema,
+				LoaderOptions: func(loader *openapi3.Loader) {
+					loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+						b, ok := refs[location.String()]
+						if !ok {
+							return nil, fmt.Errorf("%s not found", location)
+						}
+						return b, nil
+					}
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_HonorMethodOverride(t *testing.T) {
+	testCases := []struct {
+		name       string
+		honor      bool
+		override   string
+		target     string
+		statusCode int
+		operation  string
+	}{
+		{"override", true, http.MethodDelete, "/orders/1?reason=duplicate", http.StatusNoContent, "deleteOrder"},
+		{"lowercase override", true, "delete", "/orders/1?reason=duplicate", http.StatusNoContent, "deleteOrder"},
+		{"override invalid", true, http.MethodDelete, "/orders/1", http.StatusUnprocessableEntity, ""},
+		{"no override", true, "", "/orders/1?reason=duplicate", http.StatusMethodNotAllowed, ""},
+		{"override not honored", false, http.MethodDelete, "/orders/1?reason=duplicate", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var operation string
+			e.POST("/orders/:id", func(c echo.Context) error {
+				input := c.Get("validator").(*openapi3filter.RequestValidationInput)
+				operation = input.Route.Operation.OperationID
+				return c.NoContent(http.StatusNoContent)
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:              "./fixtures/openapi.yaml",
+				HonorMethodOverride: tc.honor,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, nil)
+			if tc.override != "" {
+				req.Header.Set(echo.HeaderXHTTPMethodOverride, tc.override)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.operation, operation)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_HonorMethodOverride_Body(t *testing.T) {
+	e := echo.New()
+
+	var body []byte
+	e.PATCH("/validation", func(c echo.Context) error {
+		body, _ = io.ReadAll(c.Request().Body)
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:              "./fixtures/openapi.yaml",
+		HonorMethodOverride: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodPatch, "/validation", bytes.NewBufferString(`{"username": "test"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderXHTTPMethodOverride, http.MethodPost)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, `{"username": "test"}`, string(body))
+}
+
+func TestOpenAPIWithConfig_Spec(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromFile("./fixtures/openapi.yaml")
+	assert.NoError(t, err)
+
+	// strip a path before handing the document to the middleware
+	paths := openapi3.NewPaths()
+	for path, item := range doc.Paths.Map() {
+		if path != "/validation/{username}" {
+			paths.Set(path, item)
+		}
+	}
+	doc.Paths = paths
+
+	testCases := []struct {
+		name       string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"valid", "/validation", `{"username": "test"}`, http.StatusOK},
+		{"invalid", "/validation", `{"username": "a"}`, http.StatusUnprocessableEntity},
+		{"stripped path", "/validation/test", "", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST(tc.path, func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Spec:   doc,
+				Schema: "./fixtures/invalid.yaml",
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIFromFS(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+	}{
+		{"valid", `{"username": "test"}`, http.StatusOK},
+		{"invalid referenced schema", `{"username": "a"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/users", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIFromFS(os.DirFS("fixtures"), "multi/openapi.yaml"))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIFromFS_Missing(t *testing.T) {
+	assert.Panics(t, func() { OpenAPIFromFS(os.DirFS("fixtures"), "multi/missing.yaml") })
+}
+
+func TestNewOpenAPI(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config Config
+		err    string
+	}{
+		{"valid", Config{Schema: "./fixtures/openapi.yaml"}, ""},
+		{"no schema", Config{}, "either spec, schema, schemaBytes, schemaReader or schemaURL is required"},
+		{"missing file", Config{Schema: "./fixtures/missing.yaml"}, "failed loading schema file"},
+		{"invalid schema", Config{SchemaBytes: []byte("openapi: 3.0.4\ninfo: {}\npaths: {}")}, "failed validating schema"},
+		{"jwt without keyFunc", Config{Schema: "./fixtures/openapi.yaml", JWT: &JWTConfig{}}, "jwt keyFunc is required"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewOpenAPI(tc.config)
+
+			if tc.err == "" {
+				assert.NoError(t, err)
+				assert.NotNil(t, m)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+				assert.Nil(t, m)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_SchemaReader(t *testing.T) {
+	f, err := os.Open("./fixtures/openapi.yaml")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	e := echo.New()
+
+	e.POST("/validation", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{SchemaReader: f}))
+
+	req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(`{"username": "a"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+func TestOpenAPI_AuthenticationFunc(t *testing.T) {
+	authenticate := func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		req := input.RequestValidationInput.Request
+		switch input.SecuritySchemeName {
+		case "ApiKeyAuth":
+			if req.Header.Get("X-API-Key") != "secret" {
+				return errors.New("invalid api key")
+			}
+		case "BearerAuth":
+			if req.Header.Get(echo.HeaderAuthorization) != "Bearer token" {
+				return errors.New("invalid bearer token")
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name         string
+		authenticate openapi3filter.AuthenticationFunc
+		header       http.Header
+		statusCode   int
+		errors       []string
+	}{
+		{"api key", authenticate, http.Header{"X-Api-Key": {"secret"}}, http.StatusOK, nil},
+		{"bearer", authenticate, http.Header{"Authorization": {"Bearer token"}}, http.StatusOK, nil},
+		{"unauthenticated", authenticate, http.Header{"X-Api-Key": {"wrong"}}, http.StatusUnauthorized, []string{"invalid api key", "invalid bearer token"}},
+		{"default", nil, http.Header{}, http.StatusOK, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/secure", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:             "./fixtures/openapi.yaml",
+				AuthenticationFunc: tc.authenticate,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/secure", nil)
+			req.Header = tc.header
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, "Unauthorized", j.Message)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_ErrorHandler(t *testing.T) {
+	testCases := []struct {
+		name       string
+		target     string
+		body       string
+		statusCode int
+		issues     map[string][]string
+	}{
+		{
+			"parameter", "/validation/test?limit=0", `{"username": "test"}`, http.StatusUnprocessableEntity,
+			map[string][]string{"query.limit": {"parameter 'limit' in query has an error: number must be at least 1"}},
+		},
+		{
+			"body", "/validation", "", http.StatusBadRequest,
+			map[string][]string{"body": {"request body has an error: value is required but missing"}},
+		},
+		{"valid", "/validation/test?limit=1", `{"username": "test"}`, http.StatusOK, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.POST("/validation/:username", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			var issues map[string][]string
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				ErrorHandler: func(c echo.Context, status int, i map[string][]string) error {
+					issues = i
+					c.Response().Header().Set("X-Error", "validation")
+					return c.JSON(status, echo.Map{"issues": i})
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.issues, issues)
+			if tc.issues != nil {
+				assert.Equal(t, "validation", resp.Header().Get("X-Error"))
+			}
+		})
+	}
+}
+
+func TestOpenAPI_OnValidationError(t *testing.T) {
+	testCases := []struct {
+		name       string
+		target     string
+		body       string
+		devMode    bool
+		statusCode int
+		issues     map[string][]string
+	}{
+		{
+			"parameter", "/validation/test?limit=0", `{"username": "test"}`, false, http.StatusUnprocessableEntity,
+			map[string][]string{"query.limit": {"parameter 'limit' in query has an error: number must be at least 1"}},
+		},
+		{
+			"body", "/validation", "", false, http.StatusBadRequest,
+			map[string][]string{"body": {"request body has an error: value is required but missing"}},
+		},
+		{
+			"dev mode", "/validation/test?limit=0", `{"username": "test"}`, true, http.StatusUnprocessableEntity,
+			map[string][]string{"query.limit": {"parameter 'limit' in query has an error: number must be at least 1"}},
+		},
+		{"valid", "/validation/test?limit=1", `{"username": "test"}`, false, http.StatusOK, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.POST("/validation/:username", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			var issues map[string][]string
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:  "./fixtures/openapi.yaml",
+				DevMode: tc.devMode,
+				OnValidationError: func(c echo.Context, i map[string][]string) {
+					assert.False(t, c.Response().Committed)
+					issues = i
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.issues, issues)
+		})
+	}
+}
+
+func TestOpenAPI_FailFast(t *testing.T) {
+	testCases := []struct {
+		name       string
+		failFast   bool
+		target     string
+		body       string
+		statusCode int
+		errors     int
+	}{
+		{"all issues", false, "/validation/a?limit=200", `{"username": "a"}`, http.StatusUnprocessableEntity, 3},
+		{"first issue", true, "/validation/a?limit=200", `{"username": "a"}`, http.StatusUnprocessableEntity, 1},
+		{"body issue", true, "/validation/test", `{"username": "a"}`, http.StatusUnprocessableEntity, 1},
+		{"missing body", true, "/validation", "", http.StatusBadRequest, 1},
+		{"valid", true, "/validation/test?limit=1", `{"username": "test"}`, http.StatusOK, 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.POST("/validation/:username", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:   "./fixtures/openapi.yaml",
+				FailFast: tc.failFast,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.target, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.errors > 0 {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Len(t, j.Errors, tc.errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_StatusCodes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		target     string
+		body       string
+		statusCode int
+	}{
+		{"invalid", http.MethodPost, "/validation", `{"username": "a"}`, http.StatusBadRequest},
+		{"malformed", http.MethodPost, "/validation", `{`, http.StatusBadRequest},
+		{"path not found", http.MethodPost, "/nope", "", http.StatusBadRequest},
+		{"method not allowed", http.MethodGet, "/validation", "", http.StatusNotFound},
+		{"valid", http.MethodPost, "/validation", `{"username": "test"}`, http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Any("/*", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				StatusCodes: map[int]int{
+					http.StatusUnprocessableEntity: http.StatusBadRequest,
+					http.StatusNotFound:            http.StatusBadRequest,
+					http.StatusMethodNotAllowed:    http.StatusNotFound,
+				},
+			}))
+
+			req := httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
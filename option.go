@@ -0,0 +1,31 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3filter"
+)
+
+// RouteOption overrides how strictly a request is validated.
+type RouteOption struct {
+	// ExcludeRequestBody skips request body validation, including the
+	// SchemaValidators, MaxBodyProperties, StreamBodyThreshold and
+	// ParseTimeFormats checks of the middleware.
+	ExcludeRequestBody bool
+
+	// ExcludeRequestQueryParams skips query parameters validation,
+	// including RejectUnknownQueryParams.
+	ExcludeRequestQueryParams bool
+
+	// OptionalFields defines required request body fields treated as
+	// optional, as dot-separated paths like OptionalOverrides.
+	OptionalFields []string
+}
+
+// apply sets the options of ro on options.
+func (ro *RouteOption) apply(options *openapi3filter.Options) {
+	if ro.ExcludeRequestBody {
+		options.ExcludeRequestBody = true
+	}
+	if ro.ExcludeRequestQueryParams {
+		options.ExcludeRequestQueryParams = true
+	}
+}
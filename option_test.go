@@ -0,0 +1,133 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_TenantConfigResolver(t *testing.T) {
+	tenants := map[string]*RouteOption{
+		"lenient":  {ExcludeRequestBody: true},
+		"optional": {OptionalFields: []string{"username"}},
+	}
+
+	testCases := []struct {
+		name       string
+		tenant     string
+		body       string
+		statusCode int
+	}{
+		{"default missing field", "", `{}`, http.StatusUnprocessableEntity},
+		{"default invalid body", "", `{"invalid": "value"}`, http.StatusUnprocessableEntity},
+		{"lenient invalid body", "lenient", `{"invalid": "value"}`, http.StatusOK},
+		{"optional missing field", "optional", `{}`, http.StatusOK},
+		{"optional invalid body", "optional", `{"invalid": "value"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema: "./fixtures/openapi.yaml",
+				TenantConfigResolver: func(c echo.Context) *RouteOption {
+					return tenants[c.Request().Header.Get("X-Tenant")]
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.Header.Set("X-Tenant", tc.tenant)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_TenantConfigResolver_MiddlewareChecks(t *testing.T) {
+	tenants := map[string]*RouteOption{
+		"nobody":  {ExcludeRequestBody: true},
+		"noquery": {ExcludeRequestQueryParams: true},
+	}
+
+	validators := map[string]func(body any) error{
+		"dateRange": func(body any) error { return errors.New("endDate must be after startDate") },
+		"guest":     func(body any) error { return nil },
+	}
+
+	testCases := []struct {
+		name       string
+		config     Config
+		method     string
+		target     string
+		body       string
+		tenant     string
+		statusCode int
+		typed      bool
+	}{
+		{"unknown query param", Config{RejectUnknownQueryParams: true}, http.MethodGet, "/listings?pageSize=10", "", "", http.StatusBadRequest, false},
+		{"unknown query param excluded", Config{RejectUnknownQueryParams: true}, http.MethodGet, "/listings?pageSize=10", "", "noquery", http.StatusOK, false},
+		{"unknown query param body excluded", Config{RejectUnknownQueryParams: true}, http.MethodGet, "/listings?pageSize=10", "", "nobody", http.StatusBadRequest, false},
+		{"schema validators", Config{SchemaValidators: validators}, http.MethodPost, "/bookings", `{"startDate": "2024-01-01", "endDate": "2024-01-02"}`, "", http.StatusUnprocessableEntity, false},
+		{"schema validators excluded", Config{SchemaValidators: validators}, http.MethodPost, "/bookings", `{"startDate": "2024-01-01", "endDate": "2024-01-02"}`, "nobody", http.StatusOK, false},
+		{"schema validators query excluded", Config{SchemaValidators: validators}, http.MethodPost, "/bookings", `{"startDate": "2024-01-01", "endDate": "2024-01-02"}`, "noquery", http.StatusUnprocessableEntity, false},
+		{"max body properties", Config{MaxBodyProperties: 3}, http.MethodPost, "/metadata", `{"name": "test", "a": 1, "b": 2, "c": 3}`, "", http.StatusUnprocessableEntity, false},
+		{"max body properties excluded", Config{MaxBodyProperties: 3}, http.MethodPost, "/metadata", `{"name": "test", "a": 1, "b": 2, "c": 3}`, "nobody", http.StatusOK, false},
+		{"max body properties query excluded", Config{MaxBodyProperties: 3}, http.MethodPost, "/metadata", `{"name": "test", "a": 1, "b": 2, "c": 3}`, "noquery", http.StatusUnprocessableEntity, false},
+		{"stream", Config{StreamBodyThreshold: 10}, http.MethodPost, "/bulk", `[{"name":"abc"},{"name":"d"}]`, "", http.StatusUnprocessableEntity, false},
+		{"stream excluded", Config{StreamBodyThreshold: 10}, http.MethodPost, "/bulk", `[{"name":"abc"},{"name":"d"}]`, "nobody", http.StatusOK, false},
+		{"stream query excluded", Config{StreamBodyThreshold: 10}, http.MethodPost, "/bulk", `[{"name":"abc"},{"name":"d"}]`, "noquery", http.StatusUnprocessableEntity, false},
+		{"parse time formats", Config{ParseTimeFormats: true}, http.MethodPost, "/events", `{"day": "2024-02-29", "startsAt": "2024-02-29T10:30:00Z"}`, "", http.StatusOK, true},
+		{"parse time formats excluded", Config{ParseTimeFormats: true}, http.MethodPost, "/events", `{"day": "2024-02-29", "startsAt": "2024-02-29T10:30:00Z"}`, "nobody", http.StatusOK, false},
+		{"parse time formats query excluded", Config{ParseTimeFormats: true}, http.MethodPost, "/events", `{"day": "2024-02-29", "startsAt": "2024-02-29T10:30:00Z"}`, "noquery", http.StatusOK, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var typed bool
+			e.Add(tc.method, strings.Split(tc.target, "?")[0], func(c echo.Context) error {
+				if _, err := io.ReadAll(c.Request().Body); err != nil {
+					return echo.NewHTTPError(http.StatusUnprocessableEntity).SetInternal(err)
+				}
+				typed = c.Get(DefaultConfig.TypedBodyContextKey) != nil
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			config := tc.config
+			config.Schema = "./fixtures/openapi.yaml"
+			config.TenantConfigResolver = func(c echo.Context) *RouteOption {
+				return tenants[c.Request().Header.Get("X-Tenant")]
+			}
+			e.Use(OpenAPIWithConfig(config))
+
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			if tc.body != "" {
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			}
+			req.Header.Set("X-Tenant", tc.tenant)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			assert.Equal(t, tc.typed, typed)
+		})
+	}
+}
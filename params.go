@@ -0,0 +1,365 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+)
+
+// ParamsContextKey is the key the *Params of requests are stored under on
+// the echo.Context when TypedParams is enabled.
+const ParamsContextKey = "openapi_params"
+
+// Params holds the validated parameters of a request decoded according to
+// their style, explode and schema: integers as int64, numbers as float64,
+// booleans as bool, arrays as []any and objects as map[string]any. Missing
+// parameters are absent.
+type Params struct {
+	Path   map[string]any
+	Query  map[string]any
+	Header map[string]any
+	Cookie map[string]any
+}
+
+// decodeParams decodes the path parameters of route matched with values
+// pathParams and its query, header and cookie parameters present in req.
+func decodeParams(req *http.Request, route *routers.Route, pathParams map[string]string) *Params {
+	params := append(openapi3.Parameters(nil), route.PathItem.Parameters...)
+	params = append(params, route.Operation.Parameters...)
+
+	p := &Params{
+		Path:   make(map[string]any),
+		Query:  make(map[string]any),
+		Header: make(map[string]any),
+		Cookie: make(map[string]any),
+	}
+
+	query := req.URL.Query()
+	for _, ref := range params {
+		param := ref.Value
+		if param == nil {
+			continue
+		}
+
+		var (
+			value any
+			ok    bool
+		)
+		switch param.In {
+		case openapi3.ParameterInPath:
+			if v, ok := pathParams[param.Name]; ok {
+				p.Path[param.Name] = decodePathParam(v, param)
+			}
+		case openapi3.ParameterInQuery:
+			value, ok = decodeQueryParam(query, param)
+			if ok {
+				p.Query[param.Name] = value
+			}
+		case openapi3.ParameterInHeader:
+			if v := req.Header.Values(param.Name); len(v) > 0 {
+				p.Header[param.Name] = decodeParamValue(strings.Join(v, ","), param, ",", param.Explode != nil && *param.Explode)
+			}
+		case openapi3.ParameterInCookie:
+			if cookie, err := req.Cookie(param.Name); err == nil {
+				p.Cookie[param.Name] = decodeParamValue(cookie.Value, param, ",", false)
+			}
+		}
+	}
+
+	return p
+}
+
+// undeclaredQueryParams returns the sorted names of the query parameters of
+// req not declared by route. The properties of exploded form objects and the
+// name[property] keys of deepObject parameters count as declared.
+func undeclaredQueryParams(req *http.Request, route *routers.Route) []string {
+	params := append(openapi3.Parameters(nil), route.PathItem.Parameters...)
+	params = append(params, route.Operation.Parameters...)
+
+	var names []string
+	for name := range req.URL.Query() {
+		if !isQueryParamDeclared(name, params) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// isQueryParamDeclared reports whether the query key name is declared by
+// one of params.
+func isQueryParamDeclared(name string, params openapi3.Parameters) bool {
+	for _, ref := range params {
+		param := ref.Value
+		if param == nil || param.In != openapi3.ParameterInQuery {
+			continue
+		}
+		if param.Name == name {
+			return true
+		}
+
+		schema := paramSchema(param)
+		if schema == nil || schema.Type != openapi3.TypeObject || param.Content != nil {
+			continue
+		}
+		switch {
+		case param.Style == openapi3.SerializationDeepObject:
+			if strings.HasPrefix(name, param.Name+"[") && strings.HasSuffix(name, "]") {
+				return true
+			}
+		case param.Explode == nil || *param.Explode:
+			if _, ok := schema.Properties[name]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodePathParam decodes the value of the path parameter param, serialized
+// with the simple, label or matrix style.
+func decodePathParam(value string, param *openapi3.Parameter) any {
+	explode := param.Explode != nil && *param.Explode
+	sep := ","
+
+	switch param.Style {
+	case openapi3.SerializationLabel:
+		value = strings.TrimPrefix(value, ".")
+		if explode {
+			sep = "."
+		}
+	case openapi3.SerializationMatrix:
+		value = strings.TrimPrefix(value, ";"+param.Name+"=")
+		if explode {
+			sep = ";" + param.Name + "="
+		}
+	}
+
+	return decodeParamValue(value, param, sep, explode)
+}
+
+// decodeQueryParam decodes the query parameter param from query.
+func decodeQueryParam(query map[string][]string, param *openapi3.Parameter) (any, bool) {
+	schema := paramSchema(param)
+	explode := param.Explode == nil || *param.Explode
+
+	if schema != nil && schema.Type == openapi3.TypeObject && param.Content == nil {
+		switch {
+		case param.Style == openapi3.SerializationDeepObject:
+			return decodeObjectProperties(schema, func(name string) (string, bool) {
+				v, ok := query[param.Name+"["+name+"]"]
+				return firstValue(v), ok
+			})
+		case explode:
+			return decodeObjectProperties(schema, func(name string) (string, bool) {
+				v, ok := query[name]
+				return firstValue(v), ok
+			})
+		}
+	}
+
+	values, ok := query[param.Name]
+	if !ok {
+		return nil, false
+	}
+
+	if schema != nil && schema.Type == openapi3.TypeArray && param.Content == nil && explode &&
+		(param.Style == "" || param.Style == openapi3.SerializationForm) {
+		return decodeArray(schema, values), true
+	}
+
+	sep := ","
+	switch param.Style {
+	case openapi3.SerializationSpaceDelimited:
+		sep = " "
+	case openapi3.SerializationPipeDelimited:
+		sep = "|"
+	}
+
+	return decodeParamValue(firstValue(values), param, sep, false), true
+}
+
+// decodeParamValue decodes the serialized value of param, whose arrays are
+// separated by sep and whose objects, if explode is set, are serialized as
+// key=value pairs.
+func decodeParamValue(value string, param *openapi3.Parameter, sep string, explode bool) any {
+	if param.Content != nil {
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err == nil {
+			return v
+		}
+		return value
+	}
+
+	schema := paramSchema(param)
+	if schema == nil {
+		return value
+	}
+
+	switch schema.Type {
+	case openapi3.TypeArray:
+		return decodeArray(schema, strings.Split(value, sep))
+	case openapi3.TypeObject:
+		pairs := make(map[string]string)
+		parts := strings.Split(value, ",")
+		if explode {
+			for _, part := range parts {
+				if k, v, ok := strings.Cut(part, "="); ok {
+					pairs[k] = v
+				}
+			}
+		} else {
+			for i := 0; i+1 < len(parts); i += 2 {
+				pairs[parts[i]] = parts[i+1]
+			}
+		}
+		v, _ := decodeObjectProperties(schema, func(name string) (string, bool) {
+			v, ok := pairs[name]
+			return v, ok
+		})
+		return v
+	}
+
+	return decodePrimitive(schema, value)
+}
+
+// decodeArray decodes the items of an array of schema.
+func decodeArray(schema *openapi3.Schema, values []string) []any {
+	var items *openapi3.Schema
+	if schema.Items != nil {
+		items = schema.Items.Value
+	}
+
+	array := make([]any, 0, len(values))
+	for _, v := range values {
+		array = append(array, decodePrimitive(items, v))
+	}
+	return array
+}
+
+// decodeObjectProperties decodes the properties of an object of schema
+// looked up with get, reporting whether any was found.
+func decodeObjectProperties(schema *openapi3.Schema, get func(name string) (string, bool)) (map[string]any, bool) {
+	object := make(map[string]any)
+	for name, ref := range schema.Properties {
+		if v, ok := get(name); ok {
+			object[name] = decodePrimitive(ref.Value, v)
+		}
+	}
+	return object, len(object) > 0
+}
+
+// decodePrimitive converts value to the type of schema, leaving it as is if
+// it can't be.
+func decodePrimitive(schema *openapi3.Schema, value string) any {
+	if schema == nil {
+		return value
+	}
+
+	switch schema.Type {
+	case openapi3.TypeInteger:
+		if v, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return v
+		}
+	case openapi3.TypeNumber:
+		if v, err := strconv.ParseFloat(value, 64); err == nil {
+			return v
+		}
+	case openapi3.TypeBoolean:
+		if v, err := strconv.ParseBool(value); err == nil {
+			return v
+		}
+	}
+	return value
+}
+
+// paramSchema returns the schema of param, if any.
+func paramSchema(param *openapi3.Parameter) *openapi3.Schema {
+	if param.Schema == nil {
+		return nil
+	}
+	return param.Schema.Value
+}
+
+// firstValue returns the first of values, if any.
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// params returns the *Params stored on c, if any.
+func params(c echo.Context) *Params {
+	p, _ := c.Get(ParamsContextKey).(*Params)
+	if p == nil {
+		return &Params{}
+	}
+	return p
+}
+
+// PathParam returns the decoded path parameter name, if present.
+func PathParam(c echo.Context, name string) (any, bool) {
+	v, ok := params(c).Path[name]
+	return v, ok
+}
+
+// PathInt returns the integer path parameter name, if present.
+func PathInt(c echo.Context, name string) (int, bool) {
+	v, ok := params(c).Path[name].(int64)
+	return int(v), ok
+}
+
+// QueryParam returns the decoded query parameter name, if present.
+func QueryParam(c echo.Context, name string) (any, bool) {
+	v, ok := params(c).Query[name]
+	return v, ok
+}
+
+// QueryInt returns the integer query parameter name, if present.
+func QueryInt(c echo.Context, name string) (int, bool) {
+	v, ok := params(c).Query[name].(int64)
+	return int(v), ok
+}
+
+// QueryFloat returns the number query parameter name, if present.
+func QueryFloat(c echo.Context, name string) (float64, bool) {
+	v, ok := params(c).Query[name].(float64)
+	return v, ok
+}
+
+// QueryBool returns the boolean query parameter name, if present.
+func QueryBool(c echo.Context, name string) (bool, bool) {
+	v, ok := params(c).Query[name].(bool)
+	return v, ok
+}
+
+// QueryArray returns the array query parameter name, if present.
+func QueryArray(c echo.Context, name string) ([]any, bool) {
+	v, ok := params(c).Query[name].([]any)
+	return v, ok
+}
+
+// QueryObject returns the object query parameter name, if present.
+func QueryObject(c echo.Context, name string) (map[string]any, bool) {
+	v, ok := params(c).Query[name].(map[string]any)
+	return v, ok
+}
+
+// HeaderParam returns the decoded header parameter name, if present.
+func HeaderParam(c echo.Context, name string) (any, bool) {
+	v, ok := params(c).Header[name]
+	return v, ok
+}
+
+// CookieParam returns the decoded cookie parameter name, if present.
+func CookieParam(c echo.Context, name string) (any, bool) {
+	v, ok := params(c).Cookie[name]
+	return v, ok
+}
@@ -0,0 +1,203 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_TypedParams(t *testing.T) {
+	e := echo.New()
+
+	var p *Params
+	e.GET("/listings", func(c echo.Context) error {
+		p, _ = c.Get(ParamsContextKey).(*Params)
+
+		limit, ok := QueryInt(c, "limit")
+		assert.True(t, ok)
+		assert.Equal(t, 10, limit)
+
+		active, ok := QueryBool(c, "active")
+		assert.True(t, ok)
+		assert.True(t, active)
+
+		price, ok := QueryFloat(c, "price")
+		assert.True(t, ok)
+		assert.Equal(t, 9.99, price)
+
+		ids, ok := QueryArray(c, "ids")
+		assert.True(t, ok)
+		assert.Equal(t, []any{int64(1), int64(2)}, ids)
+
+		filter, ok := QueryObject(c, "filter")
+		assert.True(t, ok)
+		assert.Equal(t, map[string]any{"status": "open", "min": int64(3)}, filter)
+
+		_, ok = QueryParam(c, "missing")
+		assert.False(t, ok)
+
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		TypedParams: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?active=true&price=9.99&ids=1&ids=2&tags=a,b&filter[status]=open&filter[min]=3", nil)
+	req.Header.Set("X-Page", "2")
+	req.Header.Set("X-Sort", "name,date")
+	req.AddCookie(&http.Cookie{Name: "beta", Value: "false"})
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, []any{"a", "b"}, p.Query["tags"])
+	assert.Equal(t, int64(2), p.Header["X-Page"])
+	assert.Equal(t, []any{"name", "date"}, p.Header["X-Sort"])
+	assert.Equal(t, false, p.Cookie["beta"])
+
+	v, ok := HeaderParam(echo.New().NewContext(req, resp), "X-Page")
+	assert.False(t, ok)
+	assert.Nil(t, v)
+}
+
+func TestOpenAPIWithConfig_TypedParams_Disabled(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/listings", func(c echo.Context) error {
+		assert.Nil(t, c.Get(ParamsContextKey))
+
+		_, ok := QueryInt(c, "limit")
+		assert.False(t, ok)
+
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPI("./fixtures/openapi.yaml"))
+
+	req := httptest.NewRequest(http.MethodGet, "/listings?limit=5", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestDecodeParamValue_Object(t *testing.T) {
+	explode := true
+	param := &openapi3.Parameter{
+		Name: "X-Filter",
+		In:   openapi3.ParameterInHeader,
+		Schema: openapi3.NewObjectSchema().
+			WithProperty("status", openapi3.NewStringSchema()).
+			WithProperty("limit", openapi3.NewIntegerSchema()).NewRef(),
+	}
+
+	assert.Equal(t, map[string]any{"status": "open", "limit": int64(5)}, decodeParamValue("status,open,limit,5", param, ",", false))
+
+	param.Explode = &explode
+	assert.Equal(t, map[string]any{"status": "open", "limit": int64(5)}, decodeParamValue("status=open,limit=5", param, ",", true))
+}
+
+func TestOpenAPIWithConfig_TypedParams_Path(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:orderId", func(c echo.Context) error {
+		id, ok := PathInt(c, "id")
+		assert.True(t, ok)
+		assert.Equal(t, 42, id)
+
+		v, ok := PathParam(c, "id")
+		assert.True(t, ok)
+		assert.Equal(t, int64(42), v)
+
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		TypedParams: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestDecodePathParam(t *testing.T) {
+	explode := true
+	testCases := []struct {
+		name     string
+		style    string
+		explode  *bool
+		value    string
+		expected any
+	}{
+		{"simple", "", nil, "1,2", []any{int64(1), int64(2)}},
+		{"label", openapi3.SerializationLabel, nil, ".1,2", []any{int64(1), int64(2)}},
+		{"label explode", openapi3.SerializationLabel, &explode, ".1.2", []any{int64(1), int64(2)}},
+		{"matrix", openapi3.SerializationMatrix, nil, ";ids=1,2", []any{int64(1), int64(2)}},
+		{"matrix explode", openapi3.SerializationMatrix, &explode, ";ids=1;ids=2", []any{int64(1), int64(2)}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			param := &openapi3.Parameter{
+				Name:    "ids",
+				In:      openapi3.ParameterInPath,
+				Style:   tc.style,
+				Explode: tc.explode,
+				Schema:  openapi3.NewArraySchema().WithItems(openapi3.NewIntegerSchema()).NewRef(),
+			}
+
+			assert.Equal(t, tc.expected, decodePathParam(tc.value, param))
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_RejectUnknownQueryParams(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/listings", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:                   "./fixtures/openapi.yaml",
+		RejectUnknownQueryParams: true,
+	}))
+
+	testCases := []struct {
+		name       string
+		query      string
+		statusCode int
+		body       string
+	}{
+		{"declared", "?limit=5&ids=1&filter[status]=open&filter[min]=3", http.StatusOK, ""},
+		{"undeclared", "?limit=5&pageSize=10", http.StatusBadRequest, `{"message":"Request error","errors":["query parameter 'pageSize' is not declared"]}`},
+		{"many undeclared", "?sort=asc&page=2", http.StatusBadRequest, `{"message":"Request error","errors":["query parameter 'page' is not declared","query parameter 'sort' is not declared"]}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/listings"+tc.query, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, resp.Body.String())
+			}
+		})
+	}
+}
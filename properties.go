@@ -0,0 +1,160 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// PropertyMode defines how readOnly properties in requests, or writeOnly
+// properties in responses, are handled.
+type PropertyMode int
+
+const (
+	// PropertyReject fails validation of bodies with such properties.
+	PropertyReject PropertyMode = iota
+	// PropertyStrip removes such properties from bodies before validation.
+	PropertyStrip
+	// PropertyIgnore allows such properties.
+	PropertyIgnore
+)
+
+// AdditionalPropertiesPolicy defines whether the additionalProperties of
+// the object schemas of a specification are overridden.
+type AdditionalPropertiesPolicy int
+
+const (
+	// AdditionalPropertiesAsIs keeps additionalProperties as specified.
+	AdditionalPropertiesAsIs AdditionalPropertiesPolicy = iota
+	// AdditionalPropertiesForbid treats object schemas as specifying
+	// additionalProperties: false.
+	AdditionalPropertiesForbid
+	// AdditionalPropertiesAllow treats object schemas as specifying
+	// additionalProperties: true.
+	AdditionalPropertiesAllow
+)
+
+// overrideAdditionalProperties applies policy to the object schemas of doc.
+// Schemas composed with, or part of, allOf, anyOf or oneOf are left as is:
+// forbidding additional properties there would reject the properties
+// declared by the other schemas of the composition. So are schemas whose
+// additionalProperties is a schema, e.g. typed maps.
+func overrideAdditionalProperties(doc *openapi3.T, policy AdditionalPropertiesPolicy) {
+	if policy == AdditionalPropertiesAsIs {
+		return
+	}
+
+	composed := make(map[*openapi3.Schema]bool)
+	walkSchemas(doc, func(s *openapi3.Schema) {
+		for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+			for _, ref := range refs {
+				if ref != nil && ref.Value != nil {
+					composed[s] = true
+					composed[ref.Value] = true
+				}
+			}
+		}
+	})
+
+	allow := policy == AdditionalPropertiesAllow
+	walkSchemas(doc, func(s *openapi3.Schema) {
+		if composed[s] || s.AdditionalProperties.Schema != nil || (s.Type != openapi3.TypeObject && len(s.Properties) == 0) {
+			return
+		}
+		s.AdditionalProperties = openapi3.AdditionalProperties{Has: &allow}
+	})
+}
+
+// stripReadOnlyProperties removes the readOnly properties of the JSON
+// request body matched by route from req.
+func stripReadOnlyProperties(req *http.Request, route *routers.Route) error {
+	schema := requestBodySchema(req, route)
+	if schema == nil {
+		return nil
+	}
+
+	b, err := readBody(req)
+	if err != nil || len(b) == 0 {
+		return err
+	}
+
+	b, err = stripProperties(schema, b, func(s *openapi3.Schema) bool { return s.ReadOnly })
+	if err != nil {
+		// left for validation to report
+		return nil
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(b))
+	req.ContentLength = int64(len(b))
+
+	return nil
+}
+
+// stripProperties removes the properties of the JSON document b matching
+// strip according to schema.
+func stripProperties(schema *openapi3.Schema, b []byte, strip func(*openapi3.Schema) bool) ([]byte, error) {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	if !stripValue(schema, v, strip, make(map[*openapi3.Schema]bool)) {
+		return b, nil
+	}
+
+	return json.Marshal(v)
+}
+
+// stripValue removes the properties of v matching strip according to schema,
+// reporting whether any was removed.
+func stripValue(schema *openapi3.Schema, v any, strip func(*openapi3.Schema) bool, visiting map[*openapi3.Schema]bool) bool {
+	if schema == nil || visiting[schema] {
+		return false
+	}
+	visiting[schema] = true
+	defer delete(visiting, schema)
+
+	stripped := false
+	for _, refs := range []openapi3.SchemaRefs{schema.AllOf, schema.AnyOf, schema.OneOf} {
+		for _, ref := range refs {
+			if ref != nil && stripValue(ref.Value, v, strip, visiting) {
+				stripped = true
+			}
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		for name, ref := range schema.Properties {
+			if ref == nil || ref.Value == nil {
+				continue
+			}
+			value, ok := v[name]
+			if !ok {
+				continue
+			}
+			if strip(ref.Value) {
+				delete(v, name)
+				stripped = true
+				continue
+			}
+			if stripValue(ref.Value, value, strip, visiting) {
+				stripped = true
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for _, item := range v {
+				if stripValue(schema.Items.Value, item, strip, visiting) {
+					stripped = true
+				}
+			}
+		}
+	}
+
+	return stripped
+}
@@ -0,0 +1,160 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_ReadOnlyProperties(t *testing.T) {
+	body := `{"user":{"id":"1","username":"test","password":"secret"},"tags":[{"name":"a","createdAt":"today"}]}`
+
+	testCases := []struct {
+		name       string
+		mode       PropertyMode
+		statusCode int
+		body       string
+		errors     []string
+	}{
+		{
+			"reject", PropertyReject, http.StatusUnprocessableEntity, "",
+			[]string{`readOnly property "id" in request`, `readOnly property "createdAt" in request`},
+		},
+		{"strip", PropertyStrip, http.StatusOK, `{"user":{"username":"test","password":"secret"},"tags":[{"name":"a"}]}`, nil},
+		{"ignore", PropertyIgnore, http.StatusOK, body, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var received string
+			e.POST("/users", func(c echo.Context) error {
+				b, err := io.ReadAll(c.Request().Body)
+				assert.NoError(t, err)
+				received = string(b)
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:             "./fixtures/openapi.yaml",
+				ReadOnlyProperties: tc.mode,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewBufferString(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.body != "" {
+				assert.JSONEq(t, tc.body, received)
+			}
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.ElementsMatch(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_AdditionalProperties(t *testing.T) {
+	testCases := []struct {
+		name       string
+		policy     AdditionalPropertiesPolicy
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"as is allows unspecified", AdditionalPropertiesAsIs, "/optional-body", `{"username":"test","extra":1}`, http.StatusOK},
+		{"as is rejects false", AdditionalPropertiesAsIs, "/validation", `{"username":"test","extra":1}`, http.StatusUnprocessableEntity},
+		{"forbid rejects unspecified", AdditionalPropertiesForbid, "/optional-body", `{"username":"test","extra":1}`, http.StatusUnprocessableEntity},
+		{"forbid rejects nested", AdditionalPropertiesForbid, "/users", `{"user":{"username":"test","password":"secret"},"tags":[{"name":"a","extra":1}]}`, http.StatusUnprocessableEntity},
+		{"forbid allows known", AdditionalPropertiesForbid, "/optional-body", `{"username":"test"}`, http.StatusOK},
+		{"allow accepts false", AdditionalPropertiesAllow, "/validation", `{"username":"test","extra":1}`, http.StatusOK},
+		{"as is validates typed", AdditionalPropertiesAsIs, "/metadata", `{"name":"x","count":"str"}`, http.StatusUnprocessableEntity},
+		{"forbid keeps typed", AdditionalPropertiesForbid, "/metadata", `{"name":"x","count":1}`, http.StatusOK},
+		{"forbid validates typed", AdditionalPropertiesForbid, "/metadata", `{"name":"x","count":"str"}`, http.StatusUnprocessableEntity},
+		{"allow keeps typed", AdditionalPropertiesAllow, "/metadata", `{"name":"x","count":1}`, http.StatusOK},
+		{"allow validates typed", AdditionalPropertiesAllow, "/metadata", `{"name":"x","count":"str"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST(tc.path, func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:               "./fixtures/openapi.yaml",
+				AdditionalProperties: tc.policy,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_AdditionalProperties_SharedSpec(t *testing.T) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+	doc, err := loader.LoadFromFile("./fixtures/openapi.yaml")
+	assert.NoError(t, err)
+
+	newEcho := func(policy AdditionalPropertiesPolicy) *echo.Echo {
+		e := echo.New()
+		e.POST("/*", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, "ok")
+		})
+		e.Use(OpenAPIWithConfig(Config{Spec: doc, AdditionalProperties: policy}))
+		return e
+	}
+
+	forbid := newEcho(AdditionalPropertiesForbid)
+	allow := newEcho(AdditionalPropertiesAllow)
+
+	testCases := []struct {
+		name       string
+		e          *echo.Echo
+		path       string
+		statusCode int
+	}{
+		{"forbid rejects unspecified", forbid, "/optional-body", http.StatusUnprocessableEntity},
+		{"allow accepts unspecified", allow, "/optional-body", http.StatusOK},
+		{"forbid rejects false", forbid, "/validation", http.StatusUnprocessableEntity},
+		{"allow accepts false", allow, "/validation", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tc.path, bytes.NewBufferString(`{"username":"test","extra":1}`))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			tc.e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+		})
+	}
+
+	schema := doc.Paths.Value("/optional-body").Post.RequestBody.Value.Content.Get(echo.MIMEApplicationJSON).Schema.Value
+	assert.Nil(t, schema.AdditionalProperties.Has, "spec changed")
+}
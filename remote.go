@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// remoteSchema fetches a schema over HTTP, remembering the validators of the
+// last response so unchanged schemas aren't downloaded and parsed again.
+type remoteSchema struct {
+	url           string
+	client        *http.Client
+	header        http.Header
+	loaderOptions func(*openapi3.Loader)
+	swagger2      bool
+	etag          string
+	lastModified  string
+}
+
+func newRemoteSchema(config Config) *remoteSchema {
+	timeout := config.SchemaURLTimeout
+	if timeout == 0 {
+		timeout = DefaultConfig.SchemaURLTimeout
+	}
+
+	return &remoteSchema{
+		url:           config.SchemaURL,
+		client:        &http.Client{Timeout: timeout},
+		header:        config.SchemaURLHeaders,
+		loaderOptions: config.LoaderOptions,
+		swagger2:      config.ConvertSwagger2,
+	}
+}
+
+// fetch returns the schema document, or nil if it hasn't changed since the
+// previous fetch.
+func (r *remoteSchema) fetch(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+
+	if r.etag != "" {
+		req.Header.Set("If-None-Match", r.etag)
+	}
+	if r.lastModified != "" {
+		req.Header.Set("If-Modified-Since", r.lastModified)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotModified:
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.etag = resp.Header.Get("ETag")
+	r.lastModified = resp.Header.Get("Last-Modified")
+
+	return b, nil
+}
+
+// String implements fmt.Stringer.
+func (r *remoteSchema) String() string {
+	return r.url
+}
+
+// load fetches and parses the schema, or returns nil if it hasn't changed
+// since the previous load.
+func (r *remoteSchema) load(ctx context.Context) (*openapi3.T, error) {
+	b, err := r.fetch(ctx)
+	if err != nil || b == nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(r.url)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.swagger2 {
+		if b, err = convertSwagger2(b); err != nil {
+			return nil, err
+		}
+	}
+
+	loader := newLoader(ctx, r.loaderOptions)
+	return loader.LoadFromDataWithPath(b, u)
+}
@@ -0,0 +1,191 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+const remoteSpec = `
+openapi: 3.0.0
+info:
+  title: Remote API
+  version: "%[1]d"
+paths:
+  /v%[1]d:
+    get:
+      responses:
+        '200':
+          description: OK
+`
+
+// newRegistry returns a fake schema registry serving remoteSpec for the
+// current version, honoring If-None-Match.
+func newRegistry(version *atomic.Int32, fail *atomic.Bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		v := version.Load()
+		etag := fmt.Sprintf(`"v%d"`, v)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("ETag", etag)
+		_, _ = fmt.Fprintf(w, remoteSpec, v)
+	}))
+}
+
+func TestRemoteSchema_Reload(t *testing.T) {
+	var version atomic.Int32
+	var fail atomic.Bool
+	version.Store(1)
+
+	ts := newRegistry(&version, &fail)
+	defer ts.Close()
+
+	ctx := context.Background()
+	config := Config{SchemaURL: ts.URL, Logger: log.New("test")}
+	r := newRemoteSchema(config)
+
+	schema, err := r.load(ctx)
+	assert.NoError(t, err)
+	s, err := newSpec(ctx, config, schema)
+	assert.NoError(t, err)
+
+	var current atomic.Pointer[spec]
+	current.Store(s)
+
+	// not modified
+	assert.NoError(t, reload(ctx, config, r, &current))
+	assert.Same(t, s, current.Load())
+
+	// failed poll keeps the current spec
+	fail.Store(true)
+	assert.Error(t, reload(ctx, config, r, &current))
+	assert.Same(t, s, current.Load())
+	fail.Store(false)
+
+	// new content
+	version.Store(2)
+	assert.NoError(t, reload(ctx, config, r, &current))
+	assert.NotSame(t, s, current.Load())
+	assert.NotNil(t, current.Load().schema.Paths.Value("/v2"))
+}
+
+func TestOpenAPIWithConfig_PollInterval(t *testing.T) {
+	var version atomic.Int32
+	var fail atomic.Bool
+	version.Store(1)
+
+	ts := newRegistry(&version, &fail)
+	defer ts.Close()
+
+	e := echo.New()
+
+	e.GET("/v2", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e.Use(OpenAPIWithConfig(Config{
+		SchemaURL:    ts.URL,
+		PollInterval: 10 * time.Millisecond,
+		Context:      ctx,
+	}))
+
+	serve := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/v2", nil)
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusNotFound, serve())
+
+	version.Store(2)
+
+	assert.Eventually(t, func() bool {
+		return serve() == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestOpenAPIFromURL(t *testing.T) {
+	var version atomic.Int32
+	var fail atomic.Bool
+	version.Store(1)
+
+	ts := newRegistry(&version, &fail)
+	defer ts.Close()
+
+	e := echo.New()
+
+	e.GET("/v1", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIFromURL(ts.URL))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestOpenAPIWithConfig_SchemaURLHeaders(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(echo.HeaderAuthorization) != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = fmt.Fprintf(w, remoteSpec, 1)
+	}))
+	defer ts.Close()
+
+	assert.PanicsWithValue(t, "failed loading schema file: unexpected status code 401", func() {
+		OpenAPIWithConfig(Config{SchemaURL: ts.URL})
+	})
+
+	assert.NotPanics(t, func() {
+		OpenAPIWithConfig(Config{
+			SchemaURL:        ts.URL,
+			SchemaURLHeaders: http.Header{echo.HeaderAuthorization: {"Bearer secret"}},
+		})
+	})
+}
+
+func TestOpenAPIWithConfig_SchemaURLTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+		}
+		_, _ = fmt.Fprintf(w, remoteSpec, 1)
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	assert.Panics(t, func() {
+		OpenAPIWithConfig(Config{
+			SchemaURL:        ts.URL,
+			SchemaURLTimeout: 10 * time.Millisecond,
+		})
+	})
+}
@@ -0,0 +1,149 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+)
+
+// responseCapture copies the body written to the wrapped http.ResponseWriter.
+type responseCapture struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseCapture) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements http.Flusher.
+func (w *responseCapture) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the wrapped http.ResponseWriter for http.ResponseController.
+func (w *responseCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// validateResponseAsync validates the response captured by w in the
+// background once it was sent, reporting mismatches to the Logger and
+// OnResponseValidationError of config.
+func validateResponseAsync(ctx context.Context, c echo.Context, config Config, input *openapi3filter.RequestValidationInput, w *responseCapture) {
+	res := c.Response()
+	if !res.Committed {
+		return
+	}
+
+	// the request and response are reused once the handler returns
+	req := c.Request().Clone(ctx)
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    req,
+			PathParams: input.PathParams,
+			Route:      input.Route,
+		},
+		Status: res.Status,
+		Header: res.Header().Clone(),
+		Options: &openapi3filter.Options{
+			MultiError: true,
+		},
+	}
+	responseValidationInput.SetBodyBytes(bytes.Clone(w.body.Bytes()))
+
+	parent := c.Request().Context()
+	go func() {
+		endSpan := startSpan(parent, config.Tracer, SpanValidateResponse, input.Route)
+		err := validateResponse(ctx, responseValidationInput)
+		endSpan(err)
+		if err == nil {
+			return
+		}
+
+		config.Logger.Errorf("%s %s: %v", req.Method, input.Route.Path, err)
+		if config.OnResponseValidationError != nil {
+			config.OnResponseValidationError(input.Route.Operation.OperationID, err)
+		}
+	}()
+}
+
+// responseBuffer holds the status and body written to it until they are
+// flushed to the wrapped http.ResponseWriter. header is a snapshot of the
+// response headers set before the handler was called.
+type responseBuffer struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+	header http.Header
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *responseBuffer) WriteHeader(code int) {
+	w.status = code
+}
+
+// Write implements http.ResponseWriter.
+func (w *responseBuffer) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(b)
+}
+
+// Flush implements http.Flusher. Flushing is deferred until the buffered
+// response is validated.
+func (w *responseBuffer) Flush() {}
+
+// flush writes the buffered status and body, if any, to the wrapped
+// http.ResponseWriter.
+func (w *responseBuffer) flush() error {
+	if w.status == 0 {
+		return nil
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, err := w.body.WriteTo(w.ResponseWriter)
+	return err
+}
+
+// validateBufferedResponse validates the response buffered by w. If it's
+// invalid the response is reset so an error can be sent instead.
+func validateBufferedResponse(ctx context.Context, c echo.Context, input *openapi3filter.RequestValidationInput, w *responseBuffer) error {
+	res := c.Response()
+	if !res.Committed {
+		return nil
+	}
+
+	responseValidationInput := &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: input,
+		Status:                 w.status,
+		Header:                 res.Header(),
+		Options: &openapi3filter.Options{
+			MultiError: true,
+		},
+	}
+	responseValidationInput.SetBodyBytes(w.body.Bytes())
+
+	err := validateResponse(ctx, responseValidationInput)
+	if err != nil {
+		// only the headers set before the handler are kept
+		for k := range res.Header() {
+			res.Header().Del(k)
+		}
+		for k, v := range w.header {
+			res.Header()[k] = v
+		}
+		res.Committed = false
+		res.Size = 0
+		w.status = 0
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_AsyncResponseValidation(t *testing.T) {
+	testCases := []struct {
+		name string
+		body string
+		err  string
+	}{
+		{"valid", `{"message":"welcome"}`, ""},
+		{"invalid", `{"invalid":"welcome"}`, "failed validating response: property 'invalid' is unsupported; message: property 'message' is missing"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/", func(c echo.Context) error {
+				return c.JSONBlob(http.StatusOK, []byte(tc.body))
+			})
+
+			logger := log.New("test")
+			logger.SetOutput(io.Discard)
+
+			errs := make(chan error, 1)
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:                  "./fixtures/openapi.yaml",
+				Logger:                  logger,
+				AsyncResponseValidation: true,
+				OnResponseValidationError: func(operationID string, err error) {
+					errs <- err
+				},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			// the response is sent regardless of validation
+			assert.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, tc.body, resp.Body.String())
+
+			select {
+			case err := <-errs:
+				assert.EqualError(t, err, tc.err)
+			case <-time.After(100 * time.Millisecond):
+				assert.Empty(t, tc.err, "mismatch not reported")
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_ValidateResponses(t *testing.T) {
+	testCases := []struct {
+		name        string
+		path        string
+		handler     echo.HandlerFunc
+		statusCode  int
+		body        string
+		contentType string
+	}{
+		{
+			"valid", "/",
+			func(c echo.Context) error { return c.JSONBlob(http.StatusOK, []byte(`{"message":"welcome"}`)) },
+			http.StatusOK, `{"message":"welcome"}`, echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			"invalid body", "/",
+			func(c echo.Context) error { return c.JSONBlob(http.StatusOK, []byte(`{"invalid":"welcome"}`)) },
+			http.StatusInternalServerError, `{"message":"Response validation error"}` + "\n", echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			"invalid content type", "/",
+			func(c echo.Context) error { return c.String(http.StatusOK, "welcome") },
+			http.StatusInternalServerError, `{"message":"Response validation error"}` + "\n", echo.MIMEApplicationJSONCharsetUTF8,
+		},
+		{
+			"no content", "/no-content",
+			func(c echo.Context) error { return c.NoContent(http.StatusNoContent) },
+			http.StatusNoContent, "", "",
+		},
+		{
+			"handler error", "/",
+			func(c echo.Context) error { return echo.NewHTTPError(http.StatusTeapot, "teapot") },
+			http.StatusTeapot, `{"message":"teapot"}` + "\n", echo.MIMEApplicationJSONCharsetUTF8,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Any(tc.path, tc.handler)
+
+			logger := log.New("test")
+			logger.SetOutput(io.Discard)
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:            "./fixtures/openapi.yaml",
+				Logger:            logger,
+				ValidateResponses: true,
+			}))
+
+			method := http.MethodGet
+			if tc.path == "/no-content" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.body, resp.Body.String())
+			assert.Equal(t, tc.contentType, resp.Header().Get(echo.HeaderContentType))
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_ValidateResponses_UpstreamHeaders(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/", func(c echo.Context) error {
+		c.Response().Header().Set("X-Handler", "handler")
+		return c.JSONBlob(http.StatusOK, []byte(`{"invalid":"welcome"}`))
+	})
+
+	logger := log.New("test")
+	logger.SetOutput(io.Discard)
+
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Response().Header().Set(echo.HeaderXRequestID, "id")
+			c.Response().Header().Set(echo.HeaderVary, echo.HeaderOrigin)
+			return next(c)
+		}
+	})
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:            "./fixtures/openapi.yaml",
+		Logger:            logger,
+		ValidateResponses: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusInternalServerError, resp.Code)
+	assert.Equal(t, "id", resp.Header().Get(echo.HeaderXRequestID))
+	assert.Equal(t, echo.HeaderOrigin, resp.Header().Get(echo.HeaderVary))
+	assert.Empty(t, resp.Header().Get("X-Handler"))
+	assert.Equal(t, echo.MIMEApplicationJSONCharsetUTF8, resp.Header().Get(echo.HeaderContentType))
+}
@@ -0,0 +1,612 @@
+package openapi
+
+import (
+	"container/list"
+	"maps"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"github.com/labstack/echo/v4"
+)
+
+// serversRouter wraps a gorillamux router so operation-level servers, which
+// gorillamux ignores, are honored. Operations declaring their own servers
+// are matched first, against those servers only.
+type serversRouter struct {
+	routers.Router
+	doc        *openapi3.T
+	operations []routers.Router
+}
+
+// newRouter creates the router used to match requests against doc. When
+// ignoreHost is true, the scheme and host of servers are ignored and
+// requests are matched on their path only.
+func newRouter(doc *openapi3.T, ignoreHost bool) (routers.Router, error) {
+	if !ignoreHost {
+		return newServersRouter(doc)
+	}
+
+	router, err := newServersRouter(withoutHosts(doc))
+	if err != nil {
+		return nil, err
+	}
+
+	return &hostlessRouter{Router: router, doc: doc}, nil
+}
+
+// newServersRouter creates a router for doc honoring operation-level servers.
+func newServersRouter(doc *openapi3.T) (routers.Router, error) {
+	r := &serversRouter{doc: doc}
+	for _, path := range doc.Paths.InMatchingOrder() {
+		pathItem := doc.Paths.Value(path)
+		operations := pathItem.Operations()
+
+		methods := make([]string, 0, len(operations))
+		for method := range operations {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			op := operations[method]
+			if op.Servers == nil || len(*op.Servers) == 0 {
+				continue
+			}
+
+			item := &openapi3.PathItem{Parameters: pathItem.Parameters, Servers: *op.Servers}
+			item.SetOperation(method, op)
+
+			opRouter, err := gorillamux.NewRouter(&openapi3.T{
+				OpenAPI: doc.OpenAPI,
+				Info:    doc.Info,
+				Servers: *op.Servers,
+				Paths:   openapi3.NewPaths(openapi3.WithPath(path, item)),
+			})
+			if err != nil {
+				return nil, err
+			}
+			r.operations = append(r.operations, opRouter)
+		}
+	}
+
+	if len(r.operations) == 0 {
+		return gorillamux.NewRouter(doc)
+	}
+
+	// operations declaring their own servers aren't served under the others
+	router, err := gorillamux.NewRouter(withoutServerOperations(doc))
+	if err != nil {
+		return nil, err
+	}
+	r.Router = &hostlessRouter{Router: router, doc: doc}
+
+	return r, nil
+}
+
+// FindRoute implements routers.Router.
+func (r *serversRouter) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	for _, router := range r.operations {
+		route, pathParams, err := router.FindRoute(req)
+		if err == nil {
+			route.Spec = r.doc
+			route.PathItem = r.doc.Paths.Value(route.Path)
+			return route, pathParams, nil
+		}
+	}
+
+	return r.Router.FindRoute(req)
+}
+
+// withoutServerOperations returns a copy of doc without the operations
+// declaring their own servers, nor the paths left without operations.
+func withoutServerOperations(doc *openapi3.T) *openapi3.T {
+	res := *doc
+
+	paths := openapi3.NewPaths()
+	for path, pathItem := range doc.Paths.Map() {
+		item := *pathItem
+		for method, op := range pathItem.Operations() {
+			if op.Servers != nil && len(*op.Servers) > 0 {
+				item.SetOperation(method, nil)
+			}
+		}
+		if len(item.Operations()) > 0 {
+			paths.Set(path, &item)
+		}
+	}
+	res.Paths = paths
+
+	return &res
+}
+
+// hostlessRouter wraps a router built from a copy of doc without servers,
+// or without their hosts, so its routes refer to doc.
+type hostlessRouter struct {
+	routers.Router
+	doc *openapi3.T
+}
+
+// FindRoute implements routers.Router.
+func (r *hostlessRouter) FindRoute(req *http.Request) (*routers.Route, map[string]string, error) {
+	route, pathParams, err := r.Router.FindRoute(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	route.Spec = r.doc
+	route.PathItem = r.doc.Paths.Value(route.Path)
+	route.Operation = route.PathItem.GetOperation(route.Method)
+
+	return route, pathParams, nil
+}
+
+// withoutHosts returns a copy of doc whose servers, at any level, only keep
+// the path of their URL.
+func withoutHosts(doc *openapi3.T) *openapi3.T {
+	return withServers(doc, serversWithoutHosts)
+}
+
+// withoutServers returns a copy of doc without servers, at any level.
+func withoutServers(doc *openapi3.T) *openapi3.T {
+	return withServers(doc, func(openapi3.Servers) openapi3.Servers { return nil })
+}
+
+// withServers returns a copy of doc whose servers, at any level, are
+// replaced by the result of fn.
+func withServers(doc *openapi3.T, fn func(openapi3.Servers) openapi3.Servers) *openapi3.T {
+	res := *doc
+	res.Servers = fn(doc.Servers)
+
+	paths := openapi3.NewPaths()
+	for path, pathItem := range doc.Paths.Map() {
+		item := *pathItem
+		item.Servers = fn(pathItem.Servers)
+
+		for method, op := range pathItem.Operations() {
+			o := *op
+			if op.Servers != nil {
+				if servers := fn(*op.Servers); servers != nil {
+					o.Servers = &servers
+				} else {
+					o.Servers = nil
+				}
+			}
+			item.SetOperation(method, &o)
+		}
+
+		paths.Set(path, &item)
+	}
+	res.Paths = paths
+
+	return &res
+}
+
+// serversWithoutHosts returns copies of servers whose URL only keeps the
+// path, e.g. "/v1" for "https://{region}.example.com/v1".
+func serversWithoutHosts(servers openapi3.Servers) openapi3.Servers {
+	if servers == nil {
+		return nil
+	}
+
+	res := make(openapi3.Servers, 0, len(servers))
+	for _, server := range servers {
+		s := *server
+		if _, rest, ok := strings.Cut(s.URL, "://"); ok {
+			s.URL = "/"
+			if i := strings.Index(rest, "/"); i >= 0 {
+				s.URL = rest[i:]
+			}
+		}
+		res = append(res, &s)
+	}
+	return res
+}
+
+// serverPrefixes returns the paths of the servers of doc, at any level,
+// longest first.
+func serverPrefixes(doc *openapi3.T) []string {
+	servers := append(openapi3.Servers(nil), doc.Servers...)
+	for _, pathItem := range doc.Paths.Map() {
+		servers = append(servers, pathItem.Servers...)
+		for _, op := range pathItem.Operations() {
+			if op.Servers != nil {
+				servers = append(servers, *op.Servers...)
+			}
+		}
+	}
+
+	var prefixes []string
+	for _, server := range serversWithoutHosts(servers) {
+		prefix := "/" + strings.Trim(server.URL, "/")
+		if !slices.Contains(prefixes, prefix) {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+
+	sort.SliceStable(prefixes, func(i, j int) bool {
+		return strings.Count(prefixes[i], "/") > strings.Count(prefixes[j], "/")
+	})
+
+	return prefixes
+}
+
+// stripPathPrefix returns a copy of req whose path has the first of
+// prefixes it's under stripped, reporting whether it's under any. Segments
+// of prefixes with variables, like "{version}", match any segment.
+func stripPathPrefix(req *http.Request, prefixes []string) (*http.Request, bool) {
+	for _, prefix := range prefixes {
+		n, ok := pathPrefixSegments(req.URL.Path, prefix)
+		if !ok {
+			continue
+		}
+
+		u := *req.URL
+		u.Path = stripSegments(u.Path, n)
+		if u.RawPath != "" {
+			u.RawPath = stripSegments(u.RawPath, n)
+		}
+
+		r := req.WithContext(req.Context())
+		r.URL = &u
+		return r, true
+	}
+
+	return nil, false
+}
+
+// pathPrefixSegments returns the number of segments of prefix, reporting
+// whether p is under it.
+func pathPrefixSegments(p, prefix string) (int, bool) {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return 0, true
+	}
+
+	pre := strings.Split(prefix, "/")
+	segs := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	if len(segs) < len(pre) {
+		return 0, false
+	}
+
+	for i, seg := range pre {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && segs[i] != "" {
+			continue
+		}
+		if seg != segs[i] {
+			return 0, false
+		}
+	}
+
+	return len(pre), true
+}
+
+// stripSegments removes the first n segments of the path p.
+func stripSegments(p string, n int) string {
+	segs := strings.Split(strings.TrimPrefix(p, "/"), "/")
+	return "/" + strings.Join(segs[n:], "/")
+}
+
+// hostAllowed reports whether host matches one of allowed, which match any
+// port unless they include one and any subdomain if they start with "*.".
+func hostAllowed(host string, allowed []string) bool {
+	hostname := host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		hostname = h
+	}
+
+	for _, pattern := range allowed {
+		name := hostname
+		if _, _, err := net.SplitHostPort(pattern); err == nil {
+			name = host
+		}
+
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(strings.ToLower(name), "."+strings.ToLower(suffix)) {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(name, pattern) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// findRoute finds the route of req, served by c, using the route matched by
+// echo or the route cache if enabled. Hosts are part of the cache key unless ignoreHost is true.
+func (s *spec) findRoute(c echo.Context, req *http.Request, ignoreHost bool) (*routers.Route, map[string]string, error) {
+	if s.echo != nil {
+		return s.echo.findRoute(c, req.Method)
+	}
+
+	if s.prefixes != nil {
+		r, ok := stripPathPrefix(req, s.prefixes)
+		if !ok {
+			return nil, nil, routers.ErrPathNotFound
+		}
+		req = r
+	}
+
+	router := s.router
+	if s.hostless != nil && hostAllowed(req.Host, s.allowedHosts) {
+		router = s.hostless
+	}
+
+	if s.routes == nil || c.Path() == "" || strings.Contains(c.Path(), "*") {
+		return router.FindRoute(req)
+	}
+
+	key := req.Method + " " + c.Path()
+	if !ignoreHost {
+		key = req.Method + " " + req.Host + c.Path()
+	}
+
+	if route, ok := s.routes.get(key); ok {
+		return route, echoPathParams(c), nil
+	}
+
+	route, pathParams, err := router.FindRoute(req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only cache routes whose parameters echo decodes the same way and
+	// which are the only ones requests to the echo path can match
+	if strings.HasSuffix(echoPathTemplate(c.Path()), route.Path) && !s.routes.overlapping[route.Path] &&
+		maps.Equal(pathParams, echoPathParams(c)) {
+		s.routes.add(key, route)
+	}
+
+	return route, pathParams, nil
+}
+
+// echoPathTemplate converts the parameters of the echo path p, like ":id",
+// to those of OpenAPI path templates, like "{id}".
+func echoPathTemplate(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, ":") {
+			segs[i] = "{" + seg[1:] + "}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// echoPathParams returns the unescaped path parameters of c.
+func echoPathParams(c echo.Context) map[string]string {
+	names := c.ParamNames()
+	values := c.ParamValues()
+
+	params := make(map[string]string, len(names))
+	for i, name := range names {
+		if i >= len(values) {
+			break
+		}
+		v, err := url.PathUnescape(values[i])
+		if err != nil {
+			v = values[i]
+		}
+		params[name] = v
+	}
+	return params
+}
+
+// routeCache is an LRU cache of routes.
+type routeCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+
+	// overlapping holds the spec paths a request matching another spec path
+	// could match too, e.g. "/users/{id}" and "/users/me", whose routes
+	// can't be cached by echo path
+	overlapping map[string]bool
+}
+
+// routeCacheEntry is an entry of a routeCache.
+type routeCacheEntry struct {
+	key   string
+	route *routers.Route
+}
+
+// newRouteCache creates a routeCache holding up to size routes of doc, or
+// returns nil if size isn't positive.
+func newRouteCache(size int, doc *openapi3.T) *routeCache {
+	if size <= 0 {
+		return nil
+	}
+	return &routeCache{
+		size:        size,
+		order:       list.New(),
+		entries:     make(map[string]*list.Element),
+		overlapping: overlappingPaths(doc),
+	}
+}
+
+// overlappingPaths returns the paths of doc a request matching another path
+// of doc could match too.
+func overlappingPaths(doc *openapi3.T) map[string]bool {
+	overlapping := make(map[string]bool)
+	if doc == nil || doc.Paths == nil {
+		return overlapping
+	}
+
+	paths := doc.Paths.InMatchingOrder()
+	for i, a := range paths {
+		for _, b := range paths[i+1:] {
+			if pathsOverlap(a, b) {
+				overlapping[a] = true
+				overlapping[b] = true
+			}
+		}
+	}
+
+	return overlapping
+}
+
+// pathsOverlap reports whether a request path could match both path
+// templates a and b, segments with parameters matching any segment.
+func pathsOverlap(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(as) != len(bs) {
+		return false
+	}
+
+	for i := range as {
+		if as[i] != bs[i] && !strings.Contains(as[i], "{") && !strings.Contains(bs[i], "{") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// get returns the route cached under key, if any.
+func (rc *routeCache) get(key string) (*routers.Route, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	el, ok := rc.entries[key]
+	if !ok {
+		return nil, false
+	}
+	rc.order.MoveToFront(el)
+	return el.Value.(*routeCacheEntry).route, true
+}
+
+// add caches route under key, evicting the least recently used route if
+// the cache is full.
+func (rc *routeCache) add(key string, route *routers.Route) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if el, ok := rc.entries[key]; ok {
+		el.Value.(*routeCacheEntry).route = route
+		rc.order.MoveToFront(el)
+		return
+	}
+
+	rc.entries[key] = rc.order.PushFront(&routeCacheEntry{key: key, route: route})
+	if rc.order.Len() > rc.size {
+		el := rc.order.Back()
+		rc.order.Remove(el)
+		delete(rc.entries, el.Value.(*routeCacheEntry).key)
+	}
+}
+
+// echoRouter matches the routes of echo to the paths of a spec.
+type echoRouter struct {
+	doc   *openapi3.T
+	paths map[string]echoRoute
+}
+
+// echoRoute is a spec path, possibly under a server.
+type echoRoute struct {
+	path   string
+	server *openapi3.Server
+}
+
+// newEchoRouter creates an echoRouter for the paths of doc, under the paths
+// of its servers and prefixes.
+func newEchoRouter(doc *openapi3.T, prefixes []string) *echoRouter {
+	r := &echoRouter{doc: doc, paths: make(map[string]echoRoute)}
+
+	for _, path := range doc.Paths.InMatchingOrder() {
+		key := pathTemplateKey(path)
+		if _, ok := r.paths[key]; !ok {
+			r.paths[key] = echoRoute{path: path}
+		}
+
+		for i, server := range serversWithoutHosts(doc.Servers) {
+			base := strings.TrimSuffix(server.URL, "/")
+			if base == "" || strings.Contains(base, "{") {
+				continue
+			}
+			if _, ok := r.paths[base+key]; !ok {
+				r.paths[base+key] = echoRoute{path: path, server: doc.Servers[i]}
+			}
+		}
+
+		for _, prefix := range prefixes {
+			base := strings.TrimSuffix(prefix, "/")
+			if base == "" || strings.Contains(base, "{") {
+				continue
+			}
+			if _, ok := r.paths[base+key]; !ok {
+				r.paths[base+key] = echoRoute{path: path}
+			}
+		}
+	}
+
+	return r
+}
+
+// findRoute returns the route of the spec matching the route of c and the
+// method, with the path parameters of c.
+func (r *echoRouter) findRoute(c echo.Context, method string) (*routers.Route, map[string]string, error) {
+	er, ok := r.paths[pathTemplateKey(echoPathTemplate(c.Path()))]
+	if !ok {
+		return nil, nil, routers.ErrPathNotFound
+	}
+
+	pathItem := r.doc.Paths.Value(er.path)
+	op := pathItem.GetOperation(method)
+	if op == nil {
+		return nil, nil, routers.ErrMethodNotAllowed
+	}
+
+	echoParams := echoPathParams(c)
+	names := c.ParamNames()
+
+	pathParams := make(map[string]string)
+	for i, name := range pathTemplateParams(er.path) {
+		if i < len(names) {
+			pathParams[name] = echoParams[names[i]]
+		}
+	}
+
+	return &routers.Route{
+		Spec:      r.doc,
+		Server:    er.server,
+		Path:      er.path,
+		PathItem:  pathItem,
+		Method:    method,
+		Operation: op,
+	}, pathParams, nil
+}
+
+// pathTemplateKey returns the path template p with its parameters unnamed,
+// e.g. "/users/{}" for "/users/{id}".
+func pathTemplateKey(p string) string {
+	segs := strings.Split(p, "/")
+	for i, seg := range segs {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			segs[i] = "{}"
+		}
+	}
+	return strings.Join(segs, "/")
+}
+
+// pathTemplateParams returns the names of the parameters of the path
+// template p, in order.
+func pathTemplateParams(p string) []string {
+	var names []string
+	for _, seg := range strings.Split(p, "/") {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			names = append(names, strings.TrimSuffix(seg[1:len(seg)-1], "*"))
+		}
+	}
+	return names
+}
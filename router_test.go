@@ -0,0 +1,543 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_Operation_Servers(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"root server", http.MethodGet, "/api/users", "", http.StatusOK},
+		{"operation server", http.MethodPost, "/v2/users", `{"username": "test"}`, http.StatusOK},
+		{"operation server invalid", http.MethodPost, "/v2/users", `{}`, http.StatusUnprocessableEntity},
+		{"root server operation under operation server", http.MethodGet, "/v2/users", "", http.StatusNotFound},
+		{"operation server operation under root server", http.MethodPost, "/api/users", `{"username": "test"}`, http.StatusMethodNotAllowed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Any(tc.path, func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPI("./fixtures/servers.yaml"))
+
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_IgnoreHost(t *testing.T) {
+	testCases := []struct {
+		name       string
+		ignoreHost bool
+		target     string
+		statusCode int
+	}{
+		{"matching host", false, "https://api.example.com/v1/users/1", http.StatusOK},
+		{"mismatched host", false, "http://10.0.0.1:8080/v1/users/1", http.StatusNotFound},
+		{"mismatched host ignored", true, "http://10.0.0.1:8080/v1/users/1", http.StatusOK},
+		{"matching host ignored", true, "https://api.example.com/v1/users/1", http.StatusOK},
+		{"mismatched host ignored invalid", true, "http://10.0.0.1:8080/v1/users/abc", http.StatusUnprocessableEntity},
+		{"mismatched host ignored other path", true, "http://10.0.0.1:8080/users/1", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/v1/users/:id", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:     "./fixtures/hosts.yaml",
+				IgnoreHost: tc.ignoreHost,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_RouteCacheSize(t *testing.T) {
+	e := echo.New()
+
+	var input *openapi3filter.RequestValidationInput
+	e.GET("/orders/:id", func(c echo.Context) error {
+		input = c.Get("validator").(*openapi3filter.RequestValidationInput)
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:         "./fixtures/openapi.yaml",
+		RouteCacheSize: 10,
+	}))
+
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+		id         string
+	}{
+		{"miss", "/orders/1", http.StatusOK, "1"},
+		{"hit", "/orders/2", http.StatusOK, "2"},
+		{"hit invalid", "/orders/abc", http.StatusUnprocessableEntity, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input = nil
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.id != "" {
+				assert.Equal(t, "/orders/{id}", input.Route.Path)
+				assert.Equal(t, map[string]string{"id": tc.id}, input.PathParams)
+			}
+		})
+	}
+}
+
+const overlappingSpec = `
+openapi: 3.0.4
+info:
+  version: 1.0.0
+  title: Overlapping API
+paths:
+  /users/me:
+    get:
+      parameters:
+        - name: q
+          in: query
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Successful response
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: string
+      responses:
+        '200':
+          description: Successful response
+`
+
+func TestOpenAPIWithConfig_RouteCacheSize_Overlapping(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		SchemaBytes:    []byte(overlappingSpec),
+		RouteCacheSize: 10,
+	}))
+
+	// requests are run in order, /users/me must not be matched to the
+	// cached /users/{id} route
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+	}{
+		{"literal path", "/users/me", http.StatusUnprocessableEntity},
+		{"templated path", "/users/123", http.StatusOK},
+		{"literal path after templated path", "/users/me", http.StatusUnprocessableEntity},
+		{"literal path valid", "/users/me?q=a", http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+func TestPathsOverlap(t *testing.T) {
+	assert.True(t, pathsOverlap("/users/{id}", "/users/me"))
+	assert.True(t, pathsOverlap("/users/{id}/items", "/users/{name}/items"))
+	assert.False(t, pathsOverlap("/users/{id}", "/users/{id}/items"))
+	assert.False(t, pathsOverlap("/users/{id}", "/orders/{id}"))
+}
+
+func TestRouteCache(t *testing.T) {
+	assert.Nil(t, newRouteCache(0, nil))
+
+	rc := newRouteCache(2, nil)
+	a, b, c := &routers.Route{Path: "/a"}, &routers.Route{Path: "/b"}, &routers.Route{Path: "/c"}
+
+	rc.add("a", a)
+	rc.add("b", b)
+
+	route, ok := rc.get("a")
+	assert.True(t, ok)
+	assert.Same(t, a, route)
+
+	rc.add("c", c)
+
+	_, ok = rc.get("b")
+	assert.False(t, ok, "least recently used route should be evicted")
+
+	route, ok = rc.get("a")
+	assert.True(t, ok)
+	assert.Same(t, a, route)
+
+	route, ok = rc.get("c")
+	assert.True(t, ok)
+	assert.Same(t, c, route)
+}
+
+func TestEchoPathTemplate(t *testing.T) {
+	assert.Equal(t, "/orders/{id}/items/{item}", echoPathTemplate("/orders/:id/items/:item"))
+	assert.Equal(t, "/orders", echoPathTemplate("/orders"))
+}
+
+func TestOpenAPIWithConfig_UseEchoRouter(t *testing.T) {
+	e := echo.New()
+
+	var input *openapi3filter.RequestValidationInput
+	handler := func(c echo.Context) error {
+		input = c.Get("validator").(*openapi3filter.RequestValidationInput)
+		return c.JSON(http.StatusOK, "ok")
+	}
+	e.GET("/orders/:orderID", handler)
+	e.POST("/orders/:orderID", handler)
+	e.GET("/unknown", handler)
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:        "./fixtures/openapi.yaml",
+		UseEchoRouter: true,
+	}))
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		statusCode int
+		pathParams map[string]string
+	}{
+		{"matched", http.MethodGet, "/orders/1", http.StatusOK, map[string]string{"id": "1"}},
+		{"matched invalid", http.MethodGet, "/orders/abc", http.StatusUnprocessableEntity, nil},
+		{"method not allowed", http.MethodPost, "/orders/1", http.StatusMethodNotAllowed, nil},
+		{"path not found", http.MethodGet, "/unknown", http.StatusNotFound, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input = nil
+			req := httptest.NewRequest(tc.method, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.pathParams != nil {
+				assert.Equal(t, "/orders/{id}", input.Route.Path)
+				assert.Equal(t, tc.pathParams, input.PathParams)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_UseEchoRouter_Servers(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/api/users", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:        "./fixtures/servers.yaml",
+		UseEchoRouter: true,
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+}
+
+func TestPathTemplateParams(t *testing.T) {
+	assert.Equal(t, []string{"id", "item"}, pathTemplateParams("/orders/{id}/items/{item}"))
+	assert.Nil(t, pathTemplateParams("/orders"))
+	assert.Equal(t, "/orders/{}/items/{}", pathTemplateKey("/orders/{id}/items/{item}"))
+}
+
+func TestOpenAPIWithConfig_RouterFunc(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/orders/:id", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	var called bool
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+		RouterFunc: func(doc *openapi3.T) (routers.Router, error) {
+			called = true
+			return legacy.NewRouter(doc)
+		},
+	}))
+
+	assert.True(t, called)
+
+	testCases := []struct {
+		name       string
+		path       string
+		statusCode int
+	}{
+		{"valid", "/orders/1", http.StatusOK},
+		{"invalid", "/orders/abc", http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_RouterFunc_Error(t *testing.T) {
+	assert.PanicsWithValue(t, "failed creating router: boom", func() {
+		OpenAPIWithConfig(Config{
+			Schema: "./fixtures/openapi.yaml",
+			RouterFunc: func(doc *openapi3.T) (routers.Router, error) {
+				return nil, errors.New("boom")
+			},
+		})
+	})
+}
+
+func TestOpenAPIWithConfig_StripPathPrefix(t *testing.T) {
+	testCases := []struct {
+		name          string
+		useEchoRouter bool
+		path          string
+		statusCode    int
+	}{
+		{"valid", false, "/api/v2/orders/1", http.StatusOK},
+		{"invalid", false, "/api/v2/orders/abc", http.StatusUnprocessableEntity},
+		{"not under prefix", false, "/orders/1", http.StatusNotFound},
+		{"echo router valid", true, "/api/v2/orders/1", http.StatusOK},
+		{"echo router invalid", true, "/api/v2/orders/abc", http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			}
+			e.GET("/api/v2/orders/:id", h)
+			e.GET("/orders/:id", h)
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:          "./fixtures/openapi.yaml",
+				StripPathPrefix: "/api/v2",
+				UseEchoRouter:   tc.useEchoRouter,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_StripServerPrefixes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		schema     string
+		method     string
+		target     string
+		body       string
+		statusCode int
+	}{
+		{"mismatched host", "./fixtures/hosts.yaml", http.MethodGet, "http://10.0.0.1:8080/v1/users/1", "", http.StatusOK},
+		{"mismatched host invalid", "./fixtures/hosts.yaml", http.MethodGet, "http://10.0.0.1:8080/v1/users/abc", "", http.StatusUnprocessableEntity},
+		{"not under server", "./fixtures/hosts.yaml", http.MethodGet, "http://10.0.0.1:8080/users/1", "", http.StatusNotFound},
+		{"root server", "./fixtures/servers.yaml", http.MethodGet, "/api/users", "", http.StatusOK},
+		{"operation server", "./fixtures/servers.yaml", http.MethodPost, "/v2/users", `{"username": "test"}`, http.StatusOK},
+		{"operation server invalid", "./fixtures/servers.yaml", http.MethodPost, "/v2/users", `{}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			}
+			e.GET("/v1/users/:id", h)
+			e.GET("/users/:id", h)
+			e.GET("/api/users", h)
+			e.POST("/v2/users", h)
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:              tc.schema,
+				StripServerPrefixes: true,
+			}))
+
+			req := httptest.NewRequest(tc.method, tc.target, bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+		})
+	}
+}
+
+func TestStripPathPrefix(t *testing.T) {
+	testCases := []struct {
+		name     string
+		path     string
+		prefixes []string
+		expected string
+		ok       bool
+	}{
+		{"prefix", "/api/v2/users", []string{"/api/v2"}, "/users", true},
+		{"prefix only", "/api/v2", []string{"/api/v2/"}, "/", true},
+		{"longest first", "/api/v2/users", []string{"/api/v2", "/api"}, "/users", true},
+		{"variable", "/api/v3/users", []string{"/api/{version}"}, "/users", true},
+		{"root", "/users", []string{"/"}, "/users", true},
+		{"partial segment", "/api/v2x/users", []string{"/api/v2"}, "", false},
+		{"no match", "/users", []string{"/api"}, "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tc.path, nil)
+
+			r, ok := stripPathPrefix(req, tc.prefixes)
+
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.expected, r.URL.Path)
+				assert.Equal(t, tc.path, req.URL.Path)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_AllowedHosts(t *testing.T) {
+	testCases := []struct {
+		name       string
+		target     string
+		statusCode int
+	}{
+		{"server host", "https://api.example.com/v1/users/1", http.StatusOK},
+		{"allowed host", "http://vhost.example.org/v1/users/1", http.StatusOK},
+		{"allowed host invalid", "http://vhost.example.org/v1/users/abc", http.StatusUnprocessableEntity},
+		{"allowed host other path", "http://vhost.example.org/users/1", http.StatusNotFound},
+		{"allowed subdomain", "http://eu.example.net/v1/users/1", http.StatusOK},
+		{"allowed port", "http://vhost.test:8080/v1/users/1", http.StatusOK},
+		{"other port", "http://vhost.test:9090/v1/users/1", http.StatusNotFound},
+		{"other host", "http://10.0.0.1:8080/v1/users/1", http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			h := func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			}
+			e.GET("/v1/users/:id", h)
+			e.GET("/users/:id", h)
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:       "./fixtures/hosts.yaml",
+				AllowedHosts: []string{"vhost.example.org", "*.example.net", "vhost.test:8080"},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestHostAllowed(t *testing.T) {
+	allowed := []string{"example.org", "*.example.net", "localhost:8080"}
+
+	testCases := []struct {
+		host     string
+		expected bool
+	}{
+		{"example.org", true},
+		{"EXAMPLE.org:443", true},
+		{"api.example.org", false},
+		{"api.example.net", true},
+		{"example.net", false},
+		{"localhost:8080", true},
+		{"localhost:9090", false},
+		{"localhost", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.host, func(t *testing.T) {
+			assert.Equal(t, tc.expected, hostAllowed(tc.host, allowed))
+		})
+	}
+}
@@ -0,0 +1,318 @@
+package openapi
+
+import (
+	"maps"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// walkSchemas calls fn once for every schema reachable from doc, including
+// recursive ones.
+func walkSchemas(doc *openapi3.T, fn func(*openapi3.Schema)) {
+	w := &schemaWalker{fn: fn, visited: make(map[*openapi3.Schema]bool)}
+
+	if doc.Components != nil {
+		for _, ref := range doc.Components.Schemas {
+			w.schema(ref)
+		}
+		for _, ref := range doc.Components.Parameters {
+			w.parameter(ref)
+		}
+		for _, ref := range doc.Components.RequestBodies {
+			if ref != nil && ref.Value != nil {
+				w.content(ref.Value.Content)
+			}
+		}
+		for _, ref := range doc.Components.Responses {
+			w.response(ref)
+		}
+		for _, ref := range doc.Components.Headers {
+			if ref != nil && ref.Value != nil {
+				w.schema(ref.Value.Schema)
+				w.content(ref.Value.Content)
+			}
+		}
+	}
+
+	if doc.Paths == nil {
+		return
+	}
+
+	for _, pathItem := range doc.Paths.Map() {
+		for _, ref := range pathItem.Parameters {
+			w.parameter(ref)
+		}
+		for _, op := range pathItem.Operations() {
+			for _, ref := range op.Parameters {
+				w.parameter(ref)
+			}
+			if op.RequestBody != nil && op.RequestBody.Value != nil {
+				w.content(op.RequestBody.Value.Content)
+			}
+			if op.Responses != nil {
+				for _, ref := range op.Responses.Map() {
+					w.response(ref)
+				}
+			}
+		}
+	}
+}
+
+type schemaWalker struct {
+	fn      func(*openapi3.Schema)
+	visited map[*openapi3.Schema]bool
+}
+
+func (w *schemaWalker) schema(ref *openapi3.SchemaRef) {
+	if ref == nil || ref.Value == nil || w.visited[ref.Value] {
+		return
+	}
+
+	s := ref.Value
+	w.visited[s] = true
+	w.fn(s)
+
+	for _, prop := range s.Properties {
+		w.schema(prop)
+	}
+	w.schema(s.Items)
+	w.schema(s.AdditionalProperties.Schema)
+	w.schema(s.Not)
+	for _, refs := range []openapi3.SchemaRefs{s.AllOf, s.AnyOf, s.OneOf} {
+		for _, item := range refs {
+			w.schema(item)
+		}
+	}
+}
+
+func (w *schemaWalker) parameter(ref *openapi3.ParameterRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	w.schema(ref.Value.Schema)
+	w.content(ref.Value.Content)
+}
+
+func (w *schemaWalker) response(ref *openapi3.ResponseRef) {
+	if ref == nil || ref.Value == nil {
+		return
+	}
+	w.content(ref.Value.Content)
+	for _, header := range ref.Value.Headers {
+		if header != nil && header.Value != nil {
+			w.schema(header.Value.Schema)
+			w.content(header.Value.Content)
+		}
+	}
+}
+
+func (w *schemaWalker) content(content openapi3.Content) {
+	for _, mediaType := range content {
+		if mediaType != nil {
+			w.schema(mediaType.Schema)
+		}
+	}
+}
+
+// copySchemas returns a copy of doc whose schemas, and everything holding
+// them, are copied, so they can be changed without changing doc.
+func copySchemas(doc *openapi3.T) *openapi3.T {
+	c := &schemaCopier{copies: make(map[*openapi3.Schema]*openapi3.Schema)}
+
+	res := *doc
+
+	if doc.Components != nil {
+		components := *doc.Components
+		if doc.Components.Schemas != nil {
+			components.Schemas = make(openapi3.Schemas, len(doc.Components.Schemas))
+			for name, ref := range doc.Components.Schemas {
+				components.Schemas[name] = c.schema(ref)
+			}
+		}
+		if doc.Components.Parameters != nil {
+			components.Parameters = make(openapi3.ParametersMap, len(doc.Components.Parameters))
+			for name, ref := range doc.Components.Parameters {
+				components.Parameters[name] = c.parameter(ref)
+			}
+		}
+		if doc.Components.RequestBodies != nil {
+			components.RequestBodies = make(openapi3.RequestBodies, len(doc.Components.RequestBodies))
+			for name, ref := range doc.Components.RequestBodies {
+				components.RequestBodies[name] = c.requestBody(ref)
+			}
+		}
+		if doc.Components.Responses != nil {
+			components.Responses = make(openapi3.ResponseBodies, len(doc.Components.Responses))
+			for name, ref := range doc.Components.Responses {
+				components.Responses[name] = c.response(ref)
+			}
+		}
+		components.Headers = c.headers(doc.Components.Headers)
+		res.Components = &components
+	}
+
+	if doc.Paths == nil {
+		return &res
+	}
+
+	paths := openapi3.NewPathsWithCapacity(doc.Paths.Len())
+	paths.Extensions = doc.Paths.Extensions
+	for path, pathItem := range doc.Paths.Map() {
+		item := *pathItem
+		item.Parameters = c.parameters(pathItem.Parameters)
+
+		for method, op := range pathItem.Operations() {
+			o := *op
+			o.Parameters = c.parameters(op.Parameters)
+			o.RequestBody = c.requestBody(op.RequestBody)
+			if op.Responses != nil {
+				responses := openapi3.NewResponsesWithCapacity(op.Responses.Len())
+				responses.Extensions = op.Responses.Extensions
+				for status, ref := range op.Responses.Map() {
+					responses.Set(status, c.response(ref))
+				}
+				o.Responses = responses
+			}
+			item.SetOperation(method, &o)
+		}
+
+		paths.Set(path, &item)
+	}
+	res.Paths = paths
+
+	return &res
+}
+
+type schemaCopier struct {
+	copies map[*openapi3.Schema]*openapi3.Schema
+}
+
+func (c *schemaCopier) schema(ref *openapi3.SchemaRef) *openapi3.SchemaRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+
+	if s, ok := c.copies[ref.Value]; ok {
+		return &openapi3.SchemaRef{Ref: ref.Ref, Value: s}
+	}
+
+	s := *ref.Value
+	c.copies[ref.Value] = &s
+
+	s.Extensions = maps.Clone(ref.Value.Extensions)
+	if ref.Value.Properties != nil {
+		s.Properties = make(openapi3.Schemas, len(ref.Value.Properties))
+		for name, prop := range ref.Value.Properties {
+			s.Properties[name] = c.schema(prop)
+		}
+	}
+	s.Items = c.schema(ref.Value.Items)
+	s.AdditionalProperties.Schema = c.schema(ref.Value.AdditionalProperties.Schema)
+	s.Not = c.schema(ref.Value.Not)
+	s.AllOf = c.schemas(ref.Value.AllOf)
+	s.AnyOf = c.schemas(ref.Value.AnyOf)
+	s.OneOf = c.schemas(ref.Value.OneOf)
+
+	return &openapi3.SchemaRef{Ref: ref.Ref, Value: &s}
+}
+
+func (c *schemaCopier) schemas(refs openapi3.SchemaRefs) openapi3.SchemaRefs {
+	if refs == nil {
+		return nil
+	}
+	res := make(openapi3.SchemaRefs, len(refs))
+	for i, ref := range refs {
+		res[i] = c.schema(ref)
+	}
+	return res
+}
+
+func (c *schemaCopier) parameters(refs openapi3.Parameters) openapi3.Parameters {
+	if refs == nil {
+		return nil
+	}
+	res := make(openapi3.Parameters, len(refs))
+	for i, ref := range refs {
+		res[i] = c.parameter(ref)
+	}
+	return res
+}
+
+func (c *schemaCopier) parameter(ref *openapi3.ParameterRef) *openapi3.ParameterRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+	p := *ref.Value
+	p.Schema = c.schema(ref.Value.Schema)
+	p.Content = c.content(ref.Value.Content)
+	return &openapi3.ParameterRef{Ref: ref.Ref, Value: &p}
+}
+
+func (c *schemaCopier) requestBody(ref *openapi3.RequestBodyRef) *openapi3.RequestBodyRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+	b := *ref.Value
+	b.Content = c.content(ref.Value.Content)
+	return &openapi3.RequestBodyRef{Ref: ref.Ref, Value: &b}
+}
+
+func (c *schemaCopier) response(ref *openapi3.ResponseRef) *openapi3.ResponseRef {
+	if ref == nil || ref.Value == nil {
+		return ref
+	}
+	r := *ref.Value
+	r.Content = c.content(ref.Value.Content)
+	r.Headers = c.headers(ref.Value.Headers)
+	return &openapi3.ResponseRef{Ref: ref.Ref, Value: &r}
+}
+
+func (c *schemaCopier) headers(headers openapi3.Headers) openapi3.Headers {
+	if headers == nil {
+		return nil
+	}
+	res := make(openapi3.Headers, len(headers))
+	for name, ref := range headers {
+		if ref == nil || ref.Value == nil {
+			res[name] = ref
+			continue
+		}
+		h := *ref.Value
+		h.Schema = c.schema(ref.Value.Schema)
+		h.Content = c.content(ref.Value.Content)
+		res[name] = &openapi3.HeaderRef{Ref: ref.Ref, Value: &h}
+	}
+	return res
+}
+
+func (c *schemaCopier) content(content openapi3.Content) openapi3.Content {
+	if content == nil {
+		return nil
+	}
+	res := make(openapi3.Content, len(content))
+	for name, mediaType := range content {
+		if mediaType == nil {
+			res[name] = nil
+			continue
+		}
+		m := *mediaType
+		m.Schema = c.schema(mediaType.Schema)
+		res[name] = &m
+	}
+	return res
+}
+
+// extensionConst is the JSON Schema "const" keyword, which OpenAPI 3.0
+// doesn't define and kin-openapi loads as an extension.
+const extensionConst = "const"
+
+// convertConst rewrites the "const" keyword of s as a single value enum.
+func convertConst(s *openapi3.Schema) {
+	v, ok := s.Extensions[extensionConst]
+	if !ok {
+		return
+	}
+
+	s.Enum = []any{v}
+	delete(s.Extensions, extensionConst)
+}
@@ -0,0 +1,202 @@
+package openapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// authError is a security scheme failure responded with status.
+type authError struct {
+	status    int
+	challenge string
+	err       error
+}
+
+// Error implements error.
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *authError) Unwrap() error {
+	return e.err
+}
+
+// validatorError converts the result of a validator returning ok and err to
+// an error, using the status and message of *echo.HTTPError errors and
+// invalid otherwise.
+func validatorError(ok bool, err error, invalid string) error {
+	var he *echo.HTTPError
+	switch {
+	case errors.As(err, &he):
+		return &authError{status: he.Code, err: errors.New(fmt.Sprint(he.Message))}
+	case err != nil:
+		return err
+	case !ok:
+		return errors.New(invalid)
+	}
+	return nil
+}
+
+// securityError returns the security requirements error in me, if any.
+func securityError(me openapi3.MultiError) *openapi3filter.SecurityRequirementsError {
+	for _, err := range me {
+		var se *openapi3filter.SecurityRequirementsError
+		if errors.As(err, &se) {
+			return se
+		}
+	}
+	return nil
+}
+
+// securityFailure responds to a request failing the security requirements
+// of err.
+func securityFailure(c echo.Context, config Config, err *openapi3filter.SecurityRequirementsError) error {
+	status := securityErrorStatus(err)
+	if status == http.StatusUnauthorized {
+		for _, challenge := range securityErrorChallenges(err) {
+			c.Response().Header().Add(echo.HeaderWWWAuthenticate, challenge)
+		}
+	}
+	return validationError(c, config, status, http.StatusText(status), map[string][]string{"security": securityErrorMessages(err)}, nil)
+}
+
+// securityErrorStatus returns the status responded for err: the status of
+// the first requirement failing with one other than 401, or 401.
+func securityErrorStatus(err *openapi3filter.SecurityRequirementsError) int {
+	for _, e := range err.Errors {
+		var ae *authError
+		if errors.As(e, &ae) && ae.status != http.StatusUnauthorized {
+			return ae.status
+		}
+	}
+	return http.StatusUnauthorized
+}
+
+// securityErrorChallenges returns the WWW-Authenticate challenges of the
+// security requirements of err failing with 401.
+func securityErrorChallenges(err *openapi3filter.SecurityRequirementsError) []string {
+	var challenges []string
+	for _, e := range err.Errors {
+		var ae *authError
+		if errors.As(e, &ae) && ae.challenge != "" && !slices.Contains(challenges, ae.challenge) {
+			challenges = append(challenges, ae.challenge)
+		}
+	}
+	return challenges
+}
+
+// securityErrorMessages returns the reason each security requirement of err
+// failed.
+func securityErrorMessages(err *openapi3filter.SecurityRequirementsError) []string {
+	msgs := make([]string, 0, len(err.Errors))
+	for _, e := range err.Errors {
+		msgs = append(msgs, strings.ReplaceAll(e.Error(), "\"", "'"))
+	}
+	return msgs
+}
+
+type echoContextKey struct{}
+
+// withEchoContext returns a copy of ctx carrying c, for the security scheme
+// validators.
+func withEchoContext(ctx context.Context, c echo.Context) context.Context {
+	return context.WithValue(ctx, echoContextKey{}, c)
+}
+
+// echoContext returns the echo.Context carried by ctx, if any.
+func echoContext(ctx context.Context) echo.Context {
+	c, _ := ctx.Value(echoContextKey{}).(echo.Context)
+	return c
+}
+
+// apiKeyAuthenticationFunc returns an openapi3filter.AuthenticationFunc
+// verifying the keys of apiKey security schemes with validator and calling
+// next for other security schemes.
+func apiKeyAuthenticationFunc(validator func(string, string, echo.Context) (bool, error), next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		scheme := input.SecurityScheme
+		if scheme.Type != "apiKey" {
+			return next(ctx, input)
+		}
+
+		req := input.RequestValidationInput.Request
+		var key string
+		switch scheme.In {
+		case openapi3.ParameterInHeader:
+			key = req.Header.Get(scheme.Name)
+		case openapi3.ParameterInQuery:
+			key = req.URL.Query().Get(scheme.Name)
+		case openapi3.ParameterInCookie:
+			if cookie, err := req.Cookie(scheme.Name); err == nil {
+				key = cookie.Value
+			}
+		}
+
+		if key == "" {
+			return fmt.Errorf("missing api key %s in %s", scheme.Name, scheme.In)
+		}
+
+		ok, err := validator(input.SecuritySchemeName, key, echoContext(ctx))
+		return validatorError(ok, err, fmt.Sprintf("invalid api key %s in %s", scheme.Name, scheme.In))
+	}
+}
+
+// scopesAuthenticationFunc returns an openapi3filter.AuthenticationFunc
+// calling next and then checking the scopes required by the security
+// requirement, if any, with scopesFunc.
+func scopesAuthenticationFunc(scopesFunc func(echo.Context, []string) error, next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		if err := next(ctx, input); err != nil {
+			return err
+		}
+
+		if len(input.Scopes) == 0 {
+			return nil
+		}
+
+		if err := scopesFunc(echoContext(ctx), input.Scopes); err != nil {
+			return &authError{status: http.StatusForbidden, err: err}
+		}
+
+		return nil
+	}
+}
+
+// basicAuthenticationFunc returns an openapi3filter.AuthenticationFunc
+// verifying the credentials of http basic security schemes with validator
+// and calling next for other security schemes.
+func basicAuthenticationFunc(validator middleware.BasicAuthValidator, realm string, next openapi3filter.AuthenticationFunc) openapi3filter.AuthenticationFunc {
+	challenge := "Basic realm=" + strconv.Quote(realm)
+
+	return func(ctx context.Context, input *openapi3filter.AuthenticationInput) error {
+		scheme := input.SecurityScheme
+		if scheme.Type != "http" || !strings.EqualFold(scheme.Scheme, "basic") {
+			return next(ctx, input)
+		}
+
+		username, password, ok := input.RequestValidationInput.Request.BasicAuth()
+		if !ok {
+			return &authError{status: http.StatusUnauthorized, challenge: challenge, err: errors.New("missing basic credentials")}
+		}
+
+		ok, err := validator(username, password, echoContext(ctx))
+		err = validatorError(ok, err, "invalid basic credentials")
+		var ae *authError
+		if err != nil && (!errors.As(err, &ae) || ae.status == http.StatusUnauthorized) {
+			return &authError{status: http.StatusUnauthorized, challenge: challenge, err: err}
+		}
+
+		return err
+	}
+}
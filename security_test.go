@@ -0,0 +1,221 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI_APIKeyValidator(t *testing.T) {
+	validator := func(name string, key string, c echo.Context) (bool, error) {
+		assert.NotNil(t, c)
+		if key == "revoked" {
+			return false, echo.NewHTTPError(http.StatusForbidden, "api key is revoked")
+		}
+		return key == name+"-secret", nil
+	}
+
+	testCases := []struct {
+		name       string
+		target     string
+		header     http.Header
+		statusCode int
+		errors     []string
+	}{
+		{"header", "/keys", http.Header{"X-Api-Key": {"ApiKeyAuth-secret"}}, http.StatusOK, nil},
+		{"query", "/keys?api_key=ApiKeyQuery-secret", http.Header{}, http.StatusOK, nil},
+		{"cookie", "/keys", http.Header{"Cookie": {"session=ApiKeyCookie-secret"}}, http.StatusOK, nil},
+		{
+			"missing", "/keys", http.Header{}, http.StatusUnauthorized,
+			[]string{"missing api key X-API-Key in header", "missing api key api_key in query", "missing api key session in cookie"},
+		},
+		{
+			"invalid", "/keys", http.Header{"X-Api-Key": {"wrong"}}, http.StatusUnauthorized,
+			[]string{"invalid api key X-API-Key in header", "missing api key api_key in query", "missing api key session in cookie"},
+		},
+		{
+			"revoked", "/keys", http.Header{"X-Api-Key": {"revoked"}}, http.StatusForbidden,
+			[]string{"api key is revoked", "missing api key api_key in query", "missing api key session in cookie"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/keys", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:          "./fixtures/openapi.yaml",
+				APIKeyValidator: validator,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			req.Header = tc.header
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusText(tc.statusCode), j.Message)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_ScopesFunc(t *testing.T) {
+	scopesFunc := func(c echo.Context, required []string) error {
+		granted, _ := c.Get("scopes").([]string)
+		for _, scope := range required {
+			if !slices.Contains(granted, scope) {
+				return fmt.Errorf("missing scope %s", scope)
+			}
+		}
+		return nil
+	}
+
+	testCases := []struct {
+		name       string
+		scopes     []string
+		statusCode int
+		errors     []string
+	}{
+		{"granted", []string{"admin:read", "admin:write"}, http.StatusOK, nil},
+		{"missing scope", []string{"admin:read"}, http.StatusForbidden, []string{"missing scope admin:write"}},
+		{"no scopes", nil, http.StatusForbidden, []string{"missing scope admin:read"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/admin", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:     "./fixtures/openapi.yaml",
+				ScopesFunc: scopesFunc,
+			}))
+
+			e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+				return func(c echo.Context) error {
+					c.Set("scopes", tc.scopes)
+					return next(c)
+				}
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, "Forbidden", j.Message)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPI_ScopesFunc_Unauthenticated(t *testing.T) {
+	e := echo.New()
+
+	e.GET("/keys", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+		APIKeyValidator: func(name string, key string, c echo.Context) (bool, error) {
+			return false, nil
+		},
+		ScopesFunc: func(c echo.Context, required []string) error {
+			return errors.New("unexpected scopes check")
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/keys", nil)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnauthorized, resp.Code)
+}
+
+func TestOpenAPI_BasicAuthValidator(t *testing.T) {
+	validator := func(username string, password string, c echo.Context) (bool, error) {
+		assert.NotNil(t, c)
+		if username == "banned" {
+			return false, echo.NewHTTPError(http.StatusForbidden, "user is banned")
+		}
+		return username == "user" && password == "pass", nil
+	}
+
+	testCases := []struct {
+		name            string
+		realm           string
+		username        string
+		password        string
+		statusCode      int
+		errors          []string
+		wwwAuthenticate string
+	}{
+		{"valid", "", "user", "pass", http.StatusOK, nil, ""},
+		{"missing", "", "", "", http.StatusUnauthorized, []string{"missing basic credentials"}, `Basic realm="Restricted"`},
+		{"invalid", "", "user", "wrong", http.StatusUnauthorized, []string{"invalid basic credentials"}, `Basic realm="Restricted"`},
+		{"realm", "Admin area", "user", "wrong", http.StatusUnauthorized, []string{"invalid basic credentials"}, `Basic realm="Admin area"`},
+		{"forbidden", "", "banned", "pass", http.StatusForbidden, []string{"user is banned"}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.GET("/basic", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:             "./fixtures/openapi.yaml",
+				BasicAuthValidator: validator,
+				BasicAuthRealm:     tc.realm,
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/basic", nil)
+			if tc.username != "" {
+				req.SetBasicAuth(tc.username, tc.password)
+			}
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+			assert.Equal(t, tc.wwwAuthenticate, resp.Header().Get(echo.HeaderWWWAuthenticate))
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+)
+
+// ExtensionSkipValidation is the vendor extension opting an operation out of
+// validation: true skips both request and response validation, while an
+// object like {"request": true, "response": false} skips them separately.
+const ExtensionSkipValidation = "x-skip-validation"
+
+// skipValidation reports whether the x-skip-validation extension of op skips
+// request and response validation.
+func skipValidation(op *openapi3.Operation) (request bool, response bool) {
+	if op == nil {
+		return false, false
+	}
+
+	switch v := op.Extensions[ExtensionSkipValidation].(type) {
+	case bool:
+		return v, v
+	case map[string]any:
+		request, _ = v["request"].(bool)
+		response, _ = v["response"].(bool)
+		return request, response
+	}
+	return false, false
+}
+
+// validateSecurity validates only the security requirements of the request
+// of input, the same way openapi3filter.ValidateRequest does.
+func validateSecurity(ctx context.Context, input *openapi3filter.RequestValidationInput) error {
+	security := input.Route.Operation.Security
+	if security == nil {
+		security = &input.Route.Spec.Security
+	}
+	if err := openapi3filter.ValidateSecurityRequirements(ctx, input, *security); err != nil {
+		return openapi3.MultiError{err}
+	}
+	return nil
+}
+
+// authorizeExempt validates only the security requirements of route for a
+// request exempted from validation, responding if they aren't met. It
+// reports whether the request is to be passed on.
+func authorizeExempt(ctx context.Context, c echo.Context, config Config, req *http.Request, route *routers.Route, pathParams map[string]string) (bool, error) {
+	input := &openapi3filter.RequestValidationInput{
+		Request:    req,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    &openapi3filter.Options{AuthenticationFunc: config.AuthenticationFunc},
+	}
+
+	err := validateSecurity(withEchoContext(ctx, c), input)
+	if me, ok := err.(openapi3.MultiError); ok {
+		if se := securityError(me); se != nil {
+			return false, securityFailure(c, config, se)
+		}
+	}
+
+	return err == nil, err
+}
+
+// authenticates reports whether config verifies the credentials of
+// requests, i.e. its AuthenticationFunc isn't the default one.
+func authenticates(config Config) bool {
+	return config.AuthenticationFunc != nil &&
+		reflect.ValueOf(config.AuthenticationFunc).Pointer() != reflect.ValueOf(openapi3filter.NoopAuthenticationFunc).Pointer()
+}
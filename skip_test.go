@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_SkipValidation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		method     string
+		header     http.Header
+		statusCode int
+	}{
+		{"request and response", http.MethodPost, http.Header{}, http.StatusOK},
+		{"request only", http.MethodPut, http.Header{"X-Api-Key": {"secret"}}, http.StatusInternalServerError},
+		{"request only unauthenticated", http.MethodPut, http.Header{}, http.StatusUnauthorized},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.Add(tc.method, "/skipped", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, map[string]any{})
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:            "./fixtures/openapi.yaml",
+				ValidateResponses: true,
+				APIKeyValidator: func(name string, key string, c echo.Context) (bool, error) {
+					return key == "secret", nil
+				},
+			}))
+
+			req := httptest.NewRequest(tc.method, "/skipped", bytes.NewBufferString(`{}`))
+			req.Header = tc.header
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+		})
+	}
+}
+
+func TestHandler_SkipValidation(t *testing.T) {
+	e := echo.New()
+	h := NewHandler()
+
+	e.POST("/skipped", func(c echo.Context) error {
+		return h.Validate(c, http.StatusOK, map[string]any{})
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/skipped", bytes.NewBufferString(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.JSONEq(t, `{}`, resp.Body.String())
+}
+
+func TestHandler_SkipValidation_ContentType(t *testing.T) {
+	e := echo.New()
+	h := NewHandler()
+
+	e.POST("/skipped", func(c echo.Context) error {
+		return h.ValidateWithContentType(c, http.StatusOK, "text/csv", "id,name\n")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema: "./fixtures/openapi.yaml",
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/skipped", bytes.NewBufferString(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/csv", resp.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "id,name\n", resp.Body.String())
+}
+
+func TestSkipValidation(t *testing.T) {
+	testCases := []struct {
+		name      string
+		extension any
+		request   bool
+		response  bool
+	}{
+		{"absent", nil, false, false},
+		{"true", true, true, true},
+		{"false", false, false, false},
+		{"request", map[string]any{"request": true}, true, false},
+		{"response", map[string]any{"response": true}, false, true},
+		{"invalid", "yes", false, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			op := &openapi3.Operation{Extensions: map[string]any{}}
+			if tc.extension != nil {
+				op.Extensions[ExtensionSkipValidation] = tc.extension
+			}
+
+			request, response := skipValidation(op)
+
+			assert.Equal(t, tc.request, request)
+			assert.Equal(t, tc.response, response)
+		})
+	}
+}
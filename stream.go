@@ -0,0 +1,163 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+var (
+	// errStreamInvalid is returned to handlers reading a streamed body once
+	// it failed validation.
+	errStreamInvalid = errors.New("request body failed validation")
+
+	// errStreamIncomplete stops the validation of a streamed body the
+	// handler didn't read to the end.
+	errStreamIncomplete = errors.New("request body not read to the end")
+)
+
+// streamBodySchema returns the schema of the JSON request body of req if
+// it's to be validated while streamed: its size, when known, exceeds
+// threshold and its schema is an array.
+func streamBodySchema(req *http.Request, route *routers.Route, threshold int64) *openapi3.Schema {
+	if threshold <= 0 || req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+
+	if req.ContentLength >= 0 && req.ContentLength <= threshold {
+		return nil
+	}
+
+	schema := requestBodySchema(req, route)
+	if schema == nil || schema.Type != openapi3.TypeArray {
+		return nil
+	}
+
+	return schema
+}
+
+// streamValidator validates a JSON array request body item by item as it's
+// read, without buffering it.
+type streamValidator struct {
+	body   io.ReadCloser
+	pw     *io.PipeWriter
+	done   chan struct{}
+	failed atomic.Bool
+	issues map[string][]string
+}
+
+// newStreamValidator starts validating body against the array schema.
+func newStreamValidator(body io.ReadCloser, schema *openapi3.Schema, fieldCase FieldNameCase) *streamValidator {
+	pr, pw := io.Pipe()
+	s := &streamValidator{body: body, pw: pw, done: make(chan struct{})}
+
+	go func() {
+		defer close(s.done)
+		s.issues = validateStream(pr, schema, fieldCase)
+		if s.issues != nil {
+			s.failed.Store(true)
+		}
+		// keep consuming so reads of the body don't block
+		_, _ = io.Copy(io.Discard, pr)
+	}()
+
+	return s
+}
+
+// Read reads the body, feeding what's read to the validation. Once the
+// body failed validation, Read returns errStreamInvalid.
+func (s *streamValidator) Read(p []byte) (int, error) {
+	if s.failed.Load() {
+		return 0, errStreamInvalid
+	}
+
+	n, err := s.body.Read(p)
+	if n > 0 {
+		_, _ = s.pw.Write(p[:n])
+	}
+
+	if err == io.EOF {
+		_ = s.pw.Close()
+		<-s.done
+		if s.issues != nil {
+			return n, errStreamInvalid
+		}
+	}
+
+	return n, err
+}
+
+func (s *streamValidator) Close() error {
+	return s.body.Close()
+}
+
+// finish stops the validation, returning the issues found, if any. Parts of
+// the body the handler didn't read aren't validated.
+func (s *streamValidator) finish() map[string][]string {
+	_ = s.pw.CloseWithError(errStreamIncomplete)
+	<-s.done
+	return s.issues
+}
+
+// validateStream validates the JSON array read from r against schema,
+// stopping at the first invalid item. The minItems and maxItems constraints
+// are enforced, uniqueItems, requiring all items to be kept, isn't.
+func validateStream(r io.Reader, schema *openapi3.Schema, fieldCase FieldNameCase) map[string][]string {
+	invalid := func(err error) map[string][]string {
+		if errors.Is(err, errStreamIncomplete) {
+			return nil
+		}
+		return map[string][]string{"body": {fmt.Sprintf("request body has an error: failed to decode request body: %v", err)}}
+	}
+
+	dec := json.NewDecoder(r)
+	tok, err := dec.Token()
+	if err != nil {
+		return invalid(err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return map[string][]string{"": {"value must be an array"}}
+	}
+
+	var items *openapi3.Schema
+	if schema.Items != nil {
+		items = schema.Items.Value
+	}
+
+	var count uint64
+	for dec.More() {
+		var item any
+		if err = dec.Decode(&item); err != nil {
+			return invalid(err)
+		}
+
+		if schema.MaxItems != nil && count+1 > *schema.MaxItems {
+			return map[string][]string{"": {fmt.Sprintf("maximum number of items is %d", *schema.MaxItems)}}
+		}
+
+		if items != nil {
+			err = items.VisitJSON(item, openapi3.MultiErrors())
+			if me := asMultiError(err); me != nil {
+				return convertErrorAt(me, fieldCase, []string{strconv.FormatUint(count, 10)})
+			}
+		}
+		count++
+	}
+
+	if _, err = dec.Token(); err != nil {
+		return invalid(err)
+	}
+
+	if count < schema.MinItems {
+		return map[string][]string{"": {fmt.Sprintf("minimum number of items is %d", schema.MinItems)}}
+	}
+
+	return nil
+}
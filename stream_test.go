@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_StreamBodyThreshold(t *testing.T) {
+	testCases := []struct {
+		name       string
+		threshold  int64
+		body       string
+		read       bool
+		statusCode int
+		called     bool
+		errors     []string
+	}{
+		{"valid", 10, `[{"name":"abc"},{"name":"def"}]`, true, http.StatusOK, true, nil},
+		{"invalid item", 10, `[{"name":"abc"},{"name":"d"}]`, true, http.StatusUnprocessableEntity, true, []string{"1.name: minimum string length is 3"}},
+		{"missing property", 10, `[{"name":"abc"},{}]`, true, http.StatusUnprocessableEntity, true, []string{"1.name: property 'name' is missing"}},
+		{"too many items", 10, `[{"name":"abc"},{"name":"abc"},{"name":"abc"},{"name":"abc"}]`, true, http.StatusUnprocessableEntity, true, []string{"maximum number of items is 3"}},
+		{"too few items", 1, `[]`, true, http.StatusUnprocessableEntity, true, []string{"minimum number of items is 1"}},
+		{"not an array", 1, `{"name":"abc"}`, true, http.StatusUnprocessableEntity, true, []string{"value must be an array"}},
+		{"unread", 10, `[{"name":"abc"},{"name":"d"}]`, false, http.StatusOK, true, nil},
+		{"below threshold", 1024, `[{"name":"abc"},{"name":"d"}]`, true, http.StatusUnprocessableEntity, false, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			var (
+				called   bool
+				received string
+				readErr  error
+			)
+			e.POST("/bulk", func(c echo.Context) error {
+				called = true
+				if tc.read {
+					var b []byte
+					b, readErr = io.ReadAll(c.Request().Body)
+					if readErr != nil {
+						return echo.NewHTTPError(http.StatusBadRequest).SetInternal(readErr)
+					}
+					received = string(b)
+				}
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:              "./fixtures/openapi.yaml",
+				StreamBodyThreshold: tc.threshold,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			assert.Equal(t, tc.called, called)
+			if tc.statusCode == http.StatusOK && tc.read {
+				assert.Equal(t, tc.body, received)
+			}
+			if tc.errors != nil {
+				assert.ErrorIs(t, readErr, errStreamInvalid)
+
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_StreamBodyThreshold_UnknownLength(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/bulk", func(c echo.Context) error {
+		if _, err := io.ReadAll(c.Request().Body); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:              "./fixtures/openapi.yaml",
+		StreamBodyThreshold: 1 << 20,
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/bulk", strings.NewReader(`[{"name":"d"}]`))
+	req.ContentLength = -1
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+}
+
+// countingReader counts the bytes read from Reader.
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
+
+func TestOpenAPIWithConfig_StreamBodyThreshold_Combined(t *testing.T) {
+	configs := map[string]Config{
+		"schema validators": {SchemaValidators: map[string]func(body any) error{
+			"dateRange": func(body any) error { return nil },
+			"guest":     func(body any) error { return nil },
+		}},
+		"parse time formats":       {ParseTimeFormats: true},
+		"strip read only property": {ReadOnlyProperties: PropertyStrip},
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+	}{
+		{"valid", `[{"name":"abc"},{"name":"def"}]`, http.StatusOK},
+		{"invalid item", `[{"name":"abc"},{"name":"d"}]`, http.StatusUnprocessableEntity},
+	}
+
+	for name, config := range configs {
+		for _, tc := range testCases {
+			t.Run(name+" "+tc.name, func(t *testing.T) {
+				e := echo.New()
+
+				body := &countingReader{Reader: strings.NewReader(tc.body)}
+				var readBefore int
+				e.POST("/bulk", func(c echo.Context) error {
+					readBefore = body.n
+					if _, err := io.ReadAll(c.Request().Body); err != nil {
+						return echo.NewHTTPError(http.StatusBadRequest).SetInternal(err)
+					}
+					return c.JSON(http.StatusOK, "ok")
+				})
+
+				config.Schema = "./fixtures/openapi.yaml"
+				config.StreamBodyThreshold = 10
+				e.Use(OpenAPIWithConfig(config))
+
+				req := httptest.NewRequest(http.MethodPost, "/bulk", body)
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				resp := httptest.NewRecorder()
+
+				e.ServeHTTP(resp, req)
+
+				assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+				assert.Zero(t, readBefore, "body read before the handler")
+			})
+		}
+	}
+}
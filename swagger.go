@@ -0,0 +1,50 @@
+package openapi
+
+import (
+	"net/url"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/invopop/yaml"
+)
+
+// convertSwagger2 converts data to an OpenAPI 3 document if it is a Swagger
+// 2.0 document, in JSON or YAML, and returns it unchanged otherwise.
+func convertSwagger2(data []byte) ([]byte, error) {
+	var version struct {
+		Swagger string `json:"swagger"`
+	}
+	if err := yaml.Unmarshal(data, &version); err != nil || version.Swagger != "2.0" {
+		return data, nil
+	}
+
+	var doc openapi2.T
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	doc3, err := openapi2conv.ToV3(&doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc3.MarshalJSON()
+}
+
+// convertSwagger2Reader makes loader convert the Swagger 2.0 documents it
+// reads to OpenAPI 3.
+func convertSwagger2Reader(loader *openapi3.Loader) {
+	read := loader.ReadFromURIFunc
+	if read == nil {
+		read = openapi3.DefaultReadFromURI
+	}
+
+	loader.ReadFromURIFunc = func(loader *openapi3.Loader, location *url.URL) ([]byte, error) {
+		data, err := read(loader, location)
+		if err != nil {
+			return nil, err
+		}
+		return convertSwagger2(data)
+	}
+}
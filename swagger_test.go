@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPI_ConvertSwagger2(t *testing.T) {
+	b, err := os.ReadFile("./fixtures/swagger.yaml")
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	configs := map[string]Config{
+		"schema":      {Schema: "./fixtures/swagger.yaml", ConvertSwagger2: true},
+		"schemaBytes": {SchemaBytes: b, ConvertSwagger2: true},
+		"schemaFS":    {Schema: "swagger.yaml", SchemaFS: os.DirFS("fixtures"), ConvertSwagger2: true},
+		"watchFile":   {Schema: "./fixtures/swagger.yaml", WatchFile: true, ConvertSwagger2: true, Context: ctx},
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		statusCode int
+	}{
+		{"valid body", http.MethodPost, "/users", `{"username": "test"}`, http.StatusOK},
+		{"invalid body", http.MethodPost, "/users", `{"username": "a"}`, http.StatusUnprocessableEntity},
+		{"valid path", http.MethodGet, "/users/1", "", http.StatusOK},
+		{"invalid path", http.MethodGet, "/users/a", "", http.StatusUnprocessableEntity},
+	}
+
+	for source, config := range configs {
+		e := echo.New()
+
+		e.POST("/users", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, "ok")
+		})
+
+		e.GET("/users/:id", func(c echo.Context) error {
+			return c.JSON(http.StatusOK, "ok")
+		})
+
+		e.Use(OpenAPIWithConfig(config))
+
+		for _, tc := range testCases {
+			t.Run(source+" "+tc.name, func(t *testing.T) {
+				req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				resp := httptest.NewRecorder()
+
+				e.ServeHTTP(resp, req)
+
+				assert.Equal(t, tc.statusCode, resp.Code)
+			})
+		}
+	}
+}
+
+func TestOpenAPI_ConvertSwagger2_Disabled(t *testing.T) {
+	assert.Panics(t, func() { OpenAPI("./fixtures/swagger.yaml") })
+}
+
+func TestConvertSwagger2_OpenAPI3(t *testing.T) {
+	data := []byte("openapi: 3.0.4\n")
+
+	b, err := convertSwagger2(data)
+	assert.NoError(t, err)
+	assert.Equal(t, data, b)
+}
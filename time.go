@@ -0,0 +1,124 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	formatDate     = "date"
+	formatDateTime = "date-time"
+)
+
+// parseTimeFormats decodes the JSON request body matched by route and
+// replaces every string declared with a date or date-time format by its
+// time.Time value.
+func parseTimeFormats(req *http.Request, route *routers.Route) (any, bool, error) {
+	schema := requestBodySchema(req, route)
+	if schema == nil {
+		return nil, false, nil
+	}
+
+	b, err := readBody(req)
+	if err != nil || len(b) == 0 {
+		return nil, false, err
+	}
+
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, false, err
+	}
+
+	return convertTimes(schema, v), true, nil
+}
+
+// requestBodySchema returns the JSON schema declared for the request body
+// of route, or nil if there is none.
+func requestBodySchema(req *http.Request, route *routers.Route) *openapi3.Schema {
+	requestBody := route.Operation.RequestBody
+	if requestBody == nil || requestBody.Value == nil {
+		return nil
+	}
+
+	contentType := req.Header.Get(echo.HeaderContentType)
+	if !strings.HasPrefix(contentType, ApplicationJSON) {
+		return nil
+	}
+
+	mediaType := requestBody.Value.Content.Get(contentType)
+	if mediaType == nil || mediaType.Schema == nil {
+		return nil
+	}
+
+	return mediaType.Schema.Value
+}
+
+// readBody reads the request body and puts it back so it can be read again.
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	b, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(b))
+
+	return b, nil
+}
+
+func convertTimes(schema *openapi3.Schema, v any) any {
+	if schema == nil {
+		return v
+	}
+
+	for _, ref := range schema.AllOf {
+		if ref != nil {
+			v = convertTimes(ref.Value, v)
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		layout := ""
+		switch schema.Format {
+		case formatDate:
+			layout = time.DateOnly
+		case formatDateTime:
+			layout = time.RFC3339Nano
+		default:
+			return val
+		}
+
+		t, err := time.Parse(layout, val)
+		if err != nil {
+			return val
+		}
+		return t
+	case map[string]any:
+		for k, item := range val {
+			if prop, ok := schema.Properties[k]; ok && prop != nil {
+				val[k] = convertTimes(prop.Value, item)
+			} else if ap := schema.AdditionalProperties.Schema; ap != nil {
+				val[k] = convertTimes(ap.Value, item)
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range val {
+				val[i] = convertTimes(schema.Items.Value, item)
+			}
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,39 @@
+package openapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_ParseTimeFormats(t *testing.T) {
+	e := echo.New()
+
+	var body map[string]any
+	e.POST("/events", func(c echo.Context) error {
+		body, _ = c.Get(DefaultConfig.TypedBodyContextKey).(map[string]any)
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:           "./fixtures/openapi.yaml",
+		ParseTimeFormats: true,
+	}))
+
+	b := []byte(`{"day": "2024-02-29", "startsAt": "2024-02-29T10:30:00Z", "name": "2024-02-29"}`)
+	req := httptest.NewRequest(http.MethodPost, "/events", bytes.NewBuffer(b))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), body["day"])
+	assert.Equal(t, time.Date(2024, 2, 29, 10, 30, 0, 0, time.UTC), body["startsAt"])
+	assert.Equal(t, "2024-02-29", body["name"])
+}
@@ -0,0 +1,81 @@
+package openapi
+
+import (
+	"context"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// Span names of the validations recorded with a Tracer.
+const (
+	SpanValidateRequest  = "openapi.validate_request"
+	SpanValidateResponse = "openapi.validate_response"
+)
+
+// Tracer starts the spans recording validations. It's a subset of an
+// OpenTelemetry trace.Tracer, which can be adapted with a few lines.
+type Tracer interface {
+	// Start starts a span named name, child of the span in ctx, if any.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer.
+type Span interface {
+	// SetAttribute sets the attribute key of the span to value, a string
+	// or an int.
+	SetAttribute(key string, value any)
+
+	// End ends the span.
+	End()
+}
+
+// startSpan starts a span named name recording the validation of route with
+// tracer, if not nil. The returned function ends it with the outcome of the
+// validation, failed with err if not nil.
+func startSpan(ctx context.Context, tracer Tracer, name string, route *routers.Route) func(err error) {
+	if tracer == nil {
+		return func(error) {}
+	}
+
+	_, span := tracer.Start(ctx, name)
+	span.SetAttribute("openapi.operation_id", route.Operation.OperationID)
+	span.SetAttribute("openapi.path", route.Path)
+	span.SetAttribute("http.request.method", route.Method)
+
+	return func(err error) {
+		outcome := "valid"
+		if err != nil {
+			outcome = "invalid"
+		}
+
+		span.SetAttribute("openapi.outcome", outcome)
+		span.SetAttribute("openapi.error_count", errorCount(err))
+		span.End()
+	}
+}
+
+// errorCount returns the number of errors of err, counting those of the
+// request and response errors wrapping several.
+func errorCount(err error) int {
+	switch err := err.(type) {
+	case nil:
+		return 0
+	case openapi3.MultiError:
+		n := 0
+		for _, e := range err {
+			n += errorCount(e)
+		}
+		return n
+	case *openapi3filter.RequestError:
+		if me := asMultiError(err.Err); me != nil {
+			return errorCount(me)
+		}
+	case *openapi3filter.ResponseError:
+		if me := asMultiError(err.Err); me != nil {
+			return errorCount(me)
+		}
+	}
+	return 1
+}
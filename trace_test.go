@@ -0,0 +1,107 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSpan struct {
+	name       string
+	attributes map[string]any
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value any) {
+	s.attributes[key] = value
+}
+
+func (s *testSpan) End() {
+	s.ended = true
+}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &testSpan{name: name, attributes: make(map[string]any)}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestOpenAPIWithConfig_Tracer(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		response any
+		spans    []testSpan
+	}{
+		{
+			"valid", `{"username":"test"}`, map[string]any{"username": "test"},
+			[]testSpan{
+				{SpanValidateRequest, map[string]any{"openapi.outcome": "valid", "openapi.error_count": 0}, true},
+				{SpanValidateResponse, map[string]any{"openapi.outcome": "valid", "openapi.error_count": 0}, true},
+			},
+		},
+		{
+			"invalid request", `{"username":"a","extra":1}`, nil,
+			[]testSpan{
+				{SpanValidateRequest, map[string]any{"openapi.outcome": "invalid", "openapi.error_count": 2}, true},
+			},
+		},
+		{
+			"invalid response", `{"username":"test"}`, map[string]any{"extra": 1},
+			[]testSpan{
+				{SpanValidateRequest, map[string]any{"openapi.outcome": "valid", "openapi.error_count": 0}, true},
+				{SpanValidateResponse, map[string]any{"openapi.outcome": "invalid", "openapi.error_count": 1}, true},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			tracer := &testTracer{}
+
+			e.POST("/validation", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, tc.response)
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:            "./fixtures/openapi.yaml",
+				ValidateResponses: true,
+				Tracer:            tracer,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/validation", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Len(t, tracer.spans, len(tc.spans))
+			for i, span := range tracer.spans {
+				expected := tc.spans[i]
+				assert.Equal(t, expected.name, span.name)
+				assert.True(t, span.ended)
+				assert.Equal(t, "createValidation", span.attributes["openapi.operation_id"])
+				assert.Equal(t, "/validation", span.attributes["openapi.path"])
+				assert.Equal(t, http.MethodPost, span.attributes["http.request.method"])
+				for k, v := range expected.attributes {
+					assert.Equal(t, v, span.attributes[k], k)
+				}
+			}
+		})
+	}
+}
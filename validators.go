@@ -0,0 +1,127 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+)
+
+// extensionValidation is the schema extension naming the SchemaValidators
+// entry to run on values of the schema.
+const extensionValidation = "x-validation"
+
+// runSchemaValidators runs the validators named by the x-validation extension
+// of schema and its subschemas on the matching parts of v, which must already
+// be valid against schema.
+func runSchemaValidators(validators map[string]func(body any) error, schema *openapi3.Schema, v any, fieldCase FieldNameCase, path []string) []FieldError {
+	if schema == nil {
+		return nil
+	}
+
+	var fes []FieldError
+	for _, ref := range schema.AllOf {
+		if ref != nil {
+			fes = append(fes, runSchemaValidators(validators, ref.Value, v, fieldCase, path)...)
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			item := val[k]
+			p := append(append([]string(nil), path...), k)
+			if prop, ok := schema.Properties[k]; ok && prop != nil {
+				fes = append(fes, runSchemaValidators(validators, prop.Value, item, fieldCase, p)...)
+			} else if ap := schema.AdditionalProperties.Schema; ap != nil {
+				fes = append(fes, runSchemaValidators(validators, ap.Value, item, fieldCase, p)...)
+			}
+		}
+	case []any:
+		if schema.Items != nil {
+			for i, item := range val {
+				p := append(append([]string(nil), path...), fmt.Sprint(i))
+				fes = append(fes, runSchemaValidators(validators, schema.Items.Value, item, fieldCase, p)...)
+			}
+		}
+	}
+
+	name, _ := schema.Extensions[extensionValidation].(string)
+	if fn, ok := validators[name]; ok {
+		if err := fn(v); err != nil {
+			fe := FieldError{In: "body", Code: extensionValidation, Message: err.Error()}
+			if len(path) > 0 {
+				fe.Field = "/" + strings.Join(fieldCase.apply(path), "/")
+			}
+			fes = append(fes, fe)
+		}
+	}
+
+	return fes
+}
+
+// checkSchemaValidators returns an error if a schema of doc names, with the
+// x-validation extension, a validator that isn't in validators.
+func checkSchemaValidators(doc *openapi3.T, validators map[string]func(body any) error) error {
+	var unknown []string
+	walkSchemas(doc, func(schema *openapi3.Schema) {
+		if name, ok := schema.Extensions[extensionValidation].(string); ok {
+			if _, ok = validators[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+	})
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("schema validator %s not found", unknown[0])
+	}
+
+	return nil
+}
+
+// fieldErrorIssues returns the messages of fes prefixed with their field,
+// keyed by field.
+func fieldErrorIssues(fes []FieldError) map[string][]string {
+	issues := make(map[string][]string)
+	for _, fe := range fes {
+		if fe.Field == "" {
+			issues[""] = append(issues[""], fe.Message)
+			continue
+		}
+		field := strings.ReplaceAll(strings.TrimPrefix(fe.Field, "/"), "/", ".")
+		issues[field] = append(issues[field], fmt.Sprintf("%s: %s", field, fe.Message))
+	}
+	return issues
+}
+
+// validateSchemaRules decodes the JSON request body matched by route and
+// runs the SchemaValidators of config on it.
+func validateSchemaRules(req *http.Request, route *routers.Route, config Config) ([]FieldError, error) {
+	schema := requestBodySchema(req, route)
+	if schema == nil {
+		return nil, nil
+	}
+
+	b, err := readBody(req)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+
+	var v any
+	if err = json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+
+	return runSchemaValidators(config.SchemaValidators, schema, v, config.FieldNameCase, nil), nil
+}
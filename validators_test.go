@@ -0,0 +1,115 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_SchemaValidators(t *testing.T) {
+	validators := map[string]func(body any) error{
+		"dateRange": func(body any) error {
+			m := body.(map[string]any)
+			if m["endDate"].(string) <= m["startDate"].(string) {
+				return errors.New("endDate must be after startDate")
+			}
+			return nil
+		},
+		"guest": func(body any) error {
+			if _, ok := body.(map[string]any)["name"]; !ok {
+				return errors.New("name is required for guests")
+			}
+			return nil
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		errors     []string
+	}{
+		{"valid", `{"startDate": "2024-01-01", "endDate": "2024-01-02"}`, http.StatusOK, nil},
+		{"invalid", `{"startDate": "2024-01-02", "endDate": "2024-01-01"}`, http.StatusUnprocessableEntity, []string{"endDate must be after startDate"}},
+		{"invalid nested", `{"startDate": "2024-01-01", "endDate": "2024-01-02", "guests": [{"name": "a"}, {"age": 1}]}`, http.StatusUnprocessableEntity, []string{"guests.1: name is required for guests"}},
+		{"schema invalid", `{"startDate": "2024-01-02"}`, http.StatusUnprocessableEntity, []string{"endDate: property 'endDate' is missing"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/bookings", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:           "./fixtures/openapi.yaml",
+				SchemaValidators: validators,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewBufferString(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code)
+
+			if tc.errors != nil {
+				j := &ValidationError{}
+				err := json.Unmarshal(resp.Body.Bytes(), j)
+				assert.NoError(t, err)
+				assert.Equal(t, tc.errors, j.Errors)
+			}
+		})
+	}
+}
+
+func TestOpenAPIWithConfig_SchemaValidators_ErrorDetailFull(t *testing.T) {
+	e := echo.New()
+
+	e.POST("/bookings", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:      "./fixtures/openapi.yaml",
+		ErrorDetail: ErrorDetailFull,
+		SchemaValidators: map[string]func(body any) error{
+			"dateRange": func(body any) error {
+				return errors.New("endDate must be after startDate")
+			},
+			"guest": func(body any) error { return nil },
+		},
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/bookings", bytes.NewBufferString(`{"startDate": "2024-01-02", "endDate": "2024-01-01"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	resp := httptest.NewRecorder()
+
+	e.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+	j := &ValidationError{}
+	err := json.Unmarshal(resp.Body.Bytes(), j)
+	assert.NoError(t, err)
+	assert.Equal(t, []FieldError{{In: "body", Code: "x-validation", Message: "endDate must be after startDate"}}, j.Details)
+}
+
+func TestNewOpenAPI_SchemaValidators_Unknown(t *testing.T) {
+	_, err := NewOpenAPI(Config{
+		Schema: "./fixtures/openapi.yaml",
+		SchemaValidators: map[string]func(body any) error{
+			"dateRange": func(body any) error { return nil },
+		},
+	})
+	assert.EqualError(t, err, "schema validator guest not found")
+}
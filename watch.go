@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// schemaSource loads a schema that can change while running.
+type schemaSource interface {
+	// load loads the schema, or returns nil if it hasn't changed since
+	// the previous load.
+	load(ctx context.Context) (*openapi3.T, error)
+
+	// String describes the source in logs.
+	String() string
+}
+
+// fileSchema loads a schema from a file, remembering the modification time
+// and size of the last load so unchanged files aren't parsed again.
+type fileSchema struct {
+	path          string
+	loaderOptions func(*openapi3.Loader)
+	swagger2      bool
+	modTime       time.Time
+	size          int64
+}
+
+func newFileSchema(config Config) *fileSchema {
+	return &fileSchema{
+		path:          config.Schema,
+		loaderOptions: config.LoaderOptions,
+		swagger2:      config.ConvertSwagger2,
+	}
+}
+
+// String implements fmt.Stringer.
+func (f *fileSchema) String() string {
+	return f.path
+}
+
+// load parses the file, or returns nil if it hasn't changed since the
+// previous load.
+func (f *fileSchema) load(ctx context.Context) (*openapi3.T, error) {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil, nil
+	}
+
+	loader := newLoader(ctx, f.loaderOptions)
+	if loader.ReadFromURIFunc == nil {
+		// the default reader caches documents for the life of the process
+		loader.ReadFromURIFunc = openapi3.ReadFromURIs(openapi3.ReadFromHTTP(http.DefaultClient), openapi3.ReadFromFile)
+	}
+	if f.swagger2 {
+		convertSwagger2Reader(loader)
+	}
+
+	schema, err := loader.LoadFromFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+
+	return schema, nil
+}
+
+// reload loads the schema of source and, if it changed, swaps it into
+// current. Requests already holding the previous spec keep using it.
+func reload(ctx context.Context, config Config, source schemaSource, current *atomic.Pointer[spec]) error {
+	schema, err := source.load(ctx)
+	if err != nil || schema == nil {
+		return err
+	}
+
+	s, err := newSpec(ctx, config, schema)
+	if err != nil {
+		return err
+	}
+	current.Store(s)
+
+	return nil
+}
+
+// poll reloads the schema of source every interval until ctx is done.
+func poll(ctx context.Context, config Config, source schemaSource, current *atomic.Pointer[spec], interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := reload(ctx, config, source, current); err != nil {
+				config.Logger.Errorf("failed reloading schema from %s: %v", source, err)
+			}
+		}
+	}
+}
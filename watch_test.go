@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/log"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeSpec writes remoteSpec for version to path, moving its modification
+// time forward so the change is noticed.
+func writeSpec(t *testing.T, path string, version int, modTime time.Time) {
+	err := os.WriteFile(path, []byte(fmt.Sprintf(remoteSpec, version)), 0o644)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestFileSchema_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	now := time.Now()
+	writeSpec(t, path, 1, now)
+
+	f := newFileSchema(Config{Schema: path})
+
+	schema, err := f.load(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, schema.Paths.Value("/v1"))
+
+	// unchanged
+	schema, err = f.load(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, schema)
+
+	writeSpec(t, path, 2, now.Add(time.Second))
+
+	schema, err = f.load(context.Background())
+	assert.NoError(t, err)
+	assert.NotNil(t, schema.Paths.Value("/v2"))
+}
+
+func TestOpenAPIWithConfig_WatchFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	now := time.Now()
+	writeSpec(t, path, 1, now)
+
+	e := echo.New()
+
+	e.GET("/*", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, "ok")
+	})
+
+	logger := log.New("test")
+	logger.SetOutput(io.Discard)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e.Use(OpenAPIWithConfig(Config{
+		Schema:       path,
+		WatchFile:    true,
+		PollInterval: 10 * time.Millisecond,
+		Logger:       logger,
+		Context:      ctx,
+	}))
+
+	serve := func(target string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		resp := httptest.NewRecorder()
+		e.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/v1"))
+	assert.Equal(t, http.StatusNotFound, serve("/v2"))
+
+	writeSpec(t, path, 2, now.Add(time.Second))
+
+	assert.Eventually(t, func() bool {
+		return serve("/v2") == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusNotFound, serve("/v1"))
+
+	// an invalid spec keeps the current one
+	err := os.WriteFile(path, []byte("invalid"), 0o644)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chtimes(path, now.Add(2*time.Second), now.Add(2*time.Second)))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, http.StatusOK, serve("/v2"))
+}
+
+func TestPoll_ContextDone(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	writeSpec(t, path, 1, time.Now())
+
+	config, err := setup(Config{Schema: path})
+	assert.NoError(t, err)
+
+	s, source, err := loadSpec(context.Background(), config)
+	assert.NoError(t, err)
+
+	var current atomic.Pointer[spec]
+	current.Store(s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		poll(ctx, config, source, &current, 10*time.Millisecond)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("still polling after the context was done")
+	}
+}
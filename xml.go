@@ -0,0 +1,184 @@
+package openapi
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/labstack/echo/v4"
+)
+
+var registerXMLBodyDecoderOnce sync.Once
+
+// registerXMLBodyDecoder registers decodeXMLBody as the body decoder of
+// kin-openapi for XML.
+func registerXMLBodyDecoder() {
+	registerXMLBodyDecoderOnce.Do(func() {
+		openapi3filter.RegisterBodyDecoder(echo.MIMEApplicationXML, decodeXMLBody)
+		openapi3filter.RegisterBodyDecoder(echo.MIMETextXML, decodeXMLBody)
+	})
+}
+
+// xmlNode is an element of an XML document.
+type xmlNode struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr `xml:",any,attr"`
+	Children []xmlNode  `xml:",any"`
+	Text     string     `xml:",chardata"`
+}
+
+// decodeXMLBody decodes an XML body into a value of schema, honoring the
+// xml object of its schemas: elements and attributes are looked up by their
+// xml name, or property name, properties with attribute set are read from
+// attributes and the items of arrays with wrapped set are read from a
+// wrapping element. Namespaces are ignored. Values that can't be converted
+// to the type of their schema are left as strings for validation to report
+// and child elements not declared are kept as strings, so
+// additionalProperties apply.
+func decodeXMLBody(body io.Reader, _ http.Header, schema *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (any, error) {
+	if schema == nil || schema.Value == nil {
+		return nil, fmt.Errorf("unsupported request body schema")
+	}
+
+	var root xmlNode
+	if err := xml.NewDecoder(body).Decode(&root); err != nil {
+		return nil, err
+	}
+
+	if x := schema.Value.XML; x != nil && x.Name != "" && x.Name != root.XMLName.Local {
+		return nil, fmt.Errorf("root element is %q but expected %q", root.XMLName.Local, x.Name)
+	}
+
+	if schema.Value.Type == openapi3.TypeArray {
+		return decodeXMLItems(schema.Value, root.Children, ""), nil
+	}
+
+	return decodeXMLNode(schema.Value, &root), nil
+}
+
+// decodeXMLNode decodes node into a value of schema.
+func decodeXMLNode(schema *openapi3.Schema, node *xmlNode) any {
+	switch {
+	case schema.Type == openapi3.TypeObject || len(schema.Properties) > 0:
+		return decodeXMLObject(schema, node)
+	case schema.Type == openapi3.TypeArray:
+		return decodeXMLItems(schema, node.Children, "")
+	}
+	return decodePrimitive(schema, strings.TrimSpace(node.Text))
+}
+
+// decodeXMLObject decodes the attributes and child elements of node into an
+// object of schema.
+func decodeXMLObject(schema *openapi3.Schema, node *xmlNode) map[string]any {
+	object := make(map[string]any)
+	declared := make(map[string]bool)
+
+	for name, ref := range schema.Properties {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		prop := ref.Value
+		elemName := xmlName(prop, name)
+		declared[elemName] = true
+
+		if prop.XML != nil && prop.XML.Attribute {
+			for _, attr := range node.Attrs {
+				if attr.Name.Local == elemName {
+					object[name] = decodePrimitive(prop, attr.Value)
+					break
+				}
+			}
+			continue
+		}
+
+		if prop.Type == openapi3.TypeArray {
+			if prop.XML != nil && prop.XML.Wrapped {
+				if wrapper := childNode(node, elemName); wrapper != nil {
+					object[name] = decodeXMLItems(prop, wrapper.Children, name)
+				}
+				continue
+			}
+
+			itemName := elemName
+			if prop.Items != nil && prop.Items.Value != nil {
+				itemName = xmlName(prop.Items.Value, elemName)
+			}
+			declared[itemName] = true
+
+			var items []xmlNode
+			for _, child := range node.Children {
+				if child.XMLName.Local == itemName {
+					items = append(items, child)
+				}
+			}
+			if len(items) > 0 {
+				object[name] = decodeXMLItems(prop, items, name)
+			}
+			continue
+		}
+
+		if child := childNode(node, elemName); child != nil {
+			object[name] = decodeXMLNode(prop, child)
+		}
+	}
+
+	for _, child := range node.Children {
+		if name := child.XMLName.Local; !declared[name] {
+			if _, ok := object[name]; !ok {
+				object[name] = strings.TrimSpace(child.Text)
+			}
+		}
+	}
+
+	return object
+}
+
+// decodeXMLItems decodes nodes into the items of an array of schema. If
+// name isn't empty, only nodes named after the items are decoded.
+func decodeXMLItems(schema *openapi3.Schema, nodes []xmlNode, name string) []any {
+	var items *openapi3.Schema
+	if schema.Items != nil {
+		items = schema.Items.Value
+	}
+
+	itemName := ""
+	if items != nil && name != "" {
+		itemName = xmlName(items, name)
+	}
+
+	array := make([]any, 0, len(nodes))
+	for i := range nodes {
+		if itemName != "" && nodes[i].XMLName.Local != itemName {
+			continue
+		}
+		if items == nil {
+			array = append(array, strings.TrimSpace(nodes[i].Text))
+			continue
+		}
+		array = append(array, decodeXMLNode(items, &nodes[i]))
+	}
+	return array
+}
+
+// xmlName returns the xml name of schema, defaulting to name.
+func xmlName(schema *openapi3.Schema, name string) string {
+	if schema.XML != nil && schema.XML.Name != "" {
+		return schema.XML.Name
+	}
+	return name
+}
+
+// childNode returns the first child element of node named name, if any.
+func childNode(node *xmlNode, name string) *xmlNode {
+	for i := range node.Children {
+		if node.Children[i].XMLName.Local == name {
+			return &node.Children[i]
+		}
+	}
+	return nil
+}
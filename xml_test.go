@@ -0,0 +1,62 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenAPIWithConfig_DecodeXMLBodies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		body       string
+		statusCode int
+		message    string
+	}{
+		{
+			"valid",
+			`<book id="1"><title>Dune</title><authors><author>Frank</author></authors><tag>a</tag><tag>b</tag><publisher><name>Chilton</name><year>1965</year></publisher></book>`,
+			http.StatusOK, "",
+		},
+		{"optional elements missing", `<book id="1"><title>Dune</title></book>`, http.StatusOK, ""},
+		{"missing attribute", `<book><title>Dune</title></book>`, http.StatusUnprocessableEntity, "id"},
+		{"invalid attribute", `<book id="x"><title>Dune</title></book>`, http.StatusUnprocessableEntity, "id"},
+		{"invalid element", `<book id="1"><title>D</title></book>`, http.StatusUnprocessableEntity, "title"},
+		{
+			"too many wrapped items",
+			`<book id="1"><title>Dune</title><authors><author>a</author><author>b</author><author>c</author></authors></book>`,
+			http.StatusUnprocessableEntity, "authors",
+		},
+		{"invalid nested element", `<book id="1"><title>Dune</title><publisher><year>x</year></publisher></book>`, http.StatusUnprocessableEntity, "name"},
+		{"wrong root element", `<novel id="1"><title>Dune</title></novel>`, http.StatusBadRequest, "root element"},
+		{"malformed", `<book id="1"><title>Dune</book>`, http.StatusBadRequest, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+
+			e.POST("/xml", func(c echo.Context) error {
+				return c.JSON(http.StatusOK, "ok")
+			})
+
+			e.Use(OpenAPIWithConfig(Config{
+				Schema:          "./fixtures/openapi.yaml",
+				DecodeXMLBodies: true,
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/xml", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationXML)
+			resp := httptest.NewRecorder()
+
+			e.ServeHTTP(resp, req)
+
+			assert.Equal(t, tc.statusCode, resp.Code, resp.Body.String())
+			assert.Contains(t, resp.Body.String(), tc.message)
+		})
+	}
+}